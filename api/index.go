@@ -13,7 +13,10 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	log := config.NewLogrus(viper)
 	db := config.NewDB(viper, log)
 	validator := config.NewValidator()
-	jwt := config.NewJWT(viper)
+	jwt := config.NewJWT(viper, log)
+	payment := config.NewPayment(viper, log)
+	mailer := config.NewMailer(viper, log)
+	storage := config.NewStorage(viper, log)
 
 	err := config.Bootstrap(&config.BootstrapConfig{
 		Viper:     viper,
@@ -21,6 +24,9 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		DB:        db,
 		Validator: validator,
 		JWT:       jwt,
+		Payment:   payment,
+		Mailer:    mailer,
+		Storage:   storage,
 	})
 	if err != nil {
 		log.Fatalf("Failed to bootstrap app: %v", err)
@@ -23,7 +23,10 @@ func main() {
 	log := config.NewLogrus(viper)
 	db := config.NewDB(viper, log)
 	validator := config.NewValidator()
-	jwt := config.NewJWT(viper)
+	jwt := config.NewJWT(viper, log)
+	payment := config.NewPayment(viper, log)
+	mailer := config.NewMailer(viper, log)
+	storage := config.NewStorage(viper, log)
 
 	err := config.Bootstrap(&config.BootstrapConfig{
 		Viper:     viper,
@@ -31,6 +34,9 @@ func main() {
 		DB:        db,
 		Validator: validator,
 		JWT:       jwt,
+		Payment:   payment,
+		Mailer:    mailer,
+		Storage:   storage,
 	})
 	if err != nil {
 		log.Fatalf("Failed to bootstrap app: %v", err)
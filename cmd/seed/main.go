@@ -0,0 +1,92 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/repository"
+	"github.com/savioruz/bake/internal/service"
+	"github.com/savioruz/bake/pkg/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// The seed command creates the admin account, or promotes an existing user to admin.
+//
+//	go run ./cmd/seed -email admin@bake.local -name Admin
+//
+// The password is read from -password or SEED_ADMIN_PASSWORD, it is only needed for a new account.
+func main() {
+	email := flag.String("email", "", "email of the admin")
+	name := flag.String("name", "Admin", "name of a new admin")
+	password := flag.String("password", "", "password of a new admin, defaults to SEED_ADMIN_PASSWORD")
+	flag.Parse()
+
+	viper := config.NewViper()
+	log := config.NewLogrus(viper)
+
+	if *email == "" {
+		log.Fatal("an -email is required")
+	}
+	if *password == "" {
+		*password = viper.GetString("SEED_ADMIN_PASSWORD")
+	}
+
+	db := config.NewDB(viper, log)
+	defer db.Close()
+
+	userRepository := repository.NewUserRepository(db)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		log.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	user, err := userRepository.GetByEmail(tx, *email)
+	switch {
+	case err == nil:
+		if err := userRepository.UpdateRole(tx, user.ID, service.RoleAdmin, now); err != nil {
+			log.Fatalf("Failed to promote user: %v", err)
+		}
+		log.Infof("Promoted %s to admin", user.Email)
+	case errors.Is(err, sql.ErrNoRows):
+		if len(*password) < 8 {
+			log.Fatal("a -password of at least 8 characters is required for a new admin")
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Fatalf("Failed to hash password: %v", err)
+		}
+
+		user = &entity.User{
+			ID:        uuid.NewString(),
+			Email:     *email,
+			Password:  string(hashedPassword),
+			Name:      *name,
+			Role:      service.RoleAdmin,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := userRepository.Create(tx, user); err != nil {
+			log.Fatalf("Failed to create admin: %v", err)
+		}
+		// The operator chose the address, there is nobody to send a verification mail to
+		if err := userRepository.SetEmailVerified(tx, user.ID, now); err != nil {
+			log.Fatalf("Failed to verify admin email: %v", err)
+		}
+		log.Infof("Created admin %s", user.Email)
+	default:
+		log.Fatalf("Failed to get user by email: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Fatalf("Failed to commit transaction: %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE products DROP CHECK chk_products_stock;
+
+COMMIT;
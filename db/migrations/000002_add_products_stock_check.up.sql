@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE products ADD CONSTRAINT chk_products_stock CHECK (stock >= 0);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE orders ADD COLUMN product_id VARCHAR(36) NULL AFTER user_id, ADD COLUMN quantity INT NULL AFTER address_id;
+
+-- Only the first line of a multi-item order survives the rollback
+UPDATE orders o
+JOIN (
+    SELECT order_id, MIN(id) AS id FROM order_items GROUP BY order_id
+) first_item ON first_item.order_id = o.id
+JOIN order_items oi ON oi.id = first_item.id
+SET o.product_id = oi.product_id, o.quantity = oi.quantity;
+
+-- Orders without items keep a NULL product_id and quantity, rolling back must not delete order history,
+-- nor let a later product delete cascade to them
+ALTER TABLE orders ADD CONSTRAINT orders_ibfk_2 FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+
+DROP TABLE IF EXISTS order_items;
+
+COMMIT;
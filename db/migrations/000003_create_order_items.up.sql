@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE order_items (
+    id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL,
+    product_id VARCHAR(36) NOT NULL,
+    quantity INT NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
+    subtotal DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT
+);
+
+INSERT INTO order_items (id, order_id, product_id, quantity, unit_price, subtotal, created_at, updated_at)
+SELECT UUID(), id, product_id, quantity, total_price / quantity, total_price, created_at, updated_at
+FROM orders;
+
+ALTER TABLE orders DROP FOREIGN KEY orders_ibfk_2;
+ALTER TABLE orders DROP COLUMN product_id, DROP COLUMN quantity;
+
+COMMIT;
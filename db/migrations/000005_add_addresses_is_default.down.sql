@@ -0,0 +1,10 @@
+BEGIN;
+
+-- The original name comes back but the constraint keeps RESTRICT, a cascade would let an address
+-- deletion wipe the orders shipped to it
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_address;
+ALTER TABLE orders ADD CONSTRAINT orders_ibfk_3 FOREIGN KEY (address_id) REFERENCES addresses(id) ON DELETE RESTRICT;
+
+ALTER TABLE addresses DROP COLUMN is_default;
+
+COMMIT;
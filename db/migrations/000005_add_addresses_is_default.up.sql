@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE addresses ADD COLUMN is_default BOOLEAN NOT NULL DEFAULT FALSE AFTER country;
+
+-- Every user starts with one default address
+UPDATE addresses a
+JOIN (
+    SELECT user_id, MIN(id) AS id FROM addresses GROUP BY user_id
+) first_address ON first_address.id = a.id
+SET a.is_default = TRUE;
+
+-- Deleting an address must never delete the orders shipped to it
+ALTER TABLE orders DROP FOREIGN KEY orders_ibfk_3;
+ALTER TABLE orders ADD CONSTRAINT fk_orders_address FOREIGN KEY (address_id) REFERENCES addresses(id) ON DELETE RESTRICT;
+
+COMMIT;
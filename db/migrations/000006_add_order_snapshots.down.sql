@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE order_items DROP COLUMN product_name;
+
+-- Orders whose address was deleted keep a NULL address_id, rolling back must not delete order history
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_address;
+ALTER TABLE orders ADD CONSTRAINT fk_orders_address FOREIGN KEY (address_id) REFERENCES addresses(id) ON DELETE RESTRICT;
+
+ALTER TABLE orders
+    DROP COLUMN shipping_address_line,
+    DROP COLUMN shipping_city,
+    DROP COLUMN shipping_state,
+    DROP COLUMN shipping_postal_code,
+    DROP COLUMN shipping_country;
+
+COMMIT;
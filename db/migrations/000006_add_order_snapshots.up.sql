@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE orders
+    ADD COLUMN shipping_address_line VARCHAR(255) NOT NULL DEFAULT '' AFTER address_id,
+    ADD COLUMN shipping_city VARCHAR(50) NOT NULL DEFAULT '' AFTER shipping_address_line,
+    ADD COLUMN shipping_state VARCHAR(50) NOT NULL DEFAULT '' AFTER shipping_city,
+    ADD COLUMN shipping_postal_code VARCHAR(20) NOT NULL DEFAULT '' AFTER shipping_state,
+    ADD COLUMN shipping_country VARCHAR(50) NOT NULL DEFAULT '' AFTER shipping_postal_code;
+
+UPDATE orders o
+JOIN addresses a ON a.id = o.address_id
+SET o.shipping_address_line = a.address_line,
+    o.shipping_city = COALESCE(a.city, ''),
+    o.shipping_state = COALESCE(a.state, ''),
+    o.shipping_postal_code = COALESCE(a.postal_code, ''),
+    o.shipping_country = COALESCE(a.country, '');
+
+-- Orders keep their snapshot when the address is removed from the address book
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_address;
+ALTER TABLE orders MODIFY address_id VARCHAR(36) NULL;
+ALTER TABLE orders ADD CONSTRAINT fk_orders_address FOREIGN KEY (address_id) REFERENCES addresses(id) ON DELETE SET NULL;
+
+ALTER TABLE order_items ADD COLUMN product_name VARCHAR(100) NOT NULL DEFAULT '' AFTER product_id;
+
+UPDATE order_items oi
+JOIN products p ON p.id = oi.product_id
+SET oi.product_name = p.name;
+
+COMMIT;
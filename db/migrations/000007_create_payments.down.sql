@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS payments;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE payments (
+    id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) NOT NULL,
+    gateway VARCHAR(50) NOT NULL,
+    intent_id VARCHAR(100) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_payments_gateway_intent (gateway, intent_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+-- Every processed webhook event is recorded so redelivered events are ignored
+CREATE TABLE payment_events (
+    id VARCHAR(100) NOT NULL,
+    gateway VARCHAR(50) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    intent_id VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (gateway, id)
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE idempotency_keys (
+    key_hash CHAR(64) PRIMARY KEY,
+    fingerprint CHAR(64) NOT NULL,
+    status_code INT NULL,
+    content_type VARCHAR(100) NULL,
+    response_body MEDIUMBLOB NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE revoked_tokens (
+    jti VARCHAR(36) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revoked_tokens_expires_at (expires_at)
+);
+
+-- Tokens of a user issued before revoked_before are denied, the store truncates it to the second
+-- so a token issued in the same second as a logout of all sessions is still accepted
+CREATE TABLE revoked_users (
+    user_id VARCHAR(36) PRIMARY KEY,
+    revoked_before TIMESTAMP(3) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    INDEX idx_revoked_users_expires_at (expires_at)
+);
+
+COMMIT;
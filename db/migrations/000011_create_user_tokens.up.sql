@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL AFTER role;
+
+-- Accounts that existed before verification was introduced are trusted as verified
+UPDATE users SET email_verified_at = created_at;
+
+-- Single use tokens sent by email, only their sha256 hash is stored
+CREATE TABLE user_tokens (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_tokens_user_purpose (user_id, purpose),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMIT;
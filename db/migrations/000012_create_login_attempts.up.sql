@@ -0,0 +1,12 @@
+BEGIN;
+
+-- One row per throttled key, keys are prefixed with their kind like "email:" or "ip:"
+CREATE TABLE login_attempts (
+    attempt_key VARCHAR(255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP(3) NULL,
+    last_failed_at TIMESTAMP(3) NOT NULL,
+    INDEX idx_login_attempts_last_failed_at (last_failed_at)
+);
+
+COMMIT;
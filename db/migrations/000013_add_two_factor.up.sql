@@ -0,0 +1,21 @@
+BEGIN;
+
+-- totp_secret is set on enrollment and only trusted once totp_enabled_at is set by a confirmed code,
+-- totp_last_counter is the last accepted time step so a code cannot be replayed
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64) NULL AFTER email_verified_at,
+    ADD COLUMN totp_enabled_at TIMESTAMP NULL AFTER totp_secret,
+    ADD COLUMN totp_last_counter BIGINT NULL AFTER totp_enabled_at;
+
+-- One time recovery codes, only their sha256 hash is stored
+CREATE TABLE recovery_codes (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_recovery_codes_user_code (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE users DROP FOREIGN KEY fk_users_role;
+
+-- Roles that did not exist before cannot fit the old column
+UPDATE users SET role = 'user' WHERE role NOT IN ('admin', 'user');
+ALTER TABLE users MODIFY role VARCHAR(10) NOT NULL DEFAULT 'user';
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+
+COMMIT;
@@ -0,0 +1,53 @@
+BEGIN;
+
+CREATE TABLE roles (
+    name VARCHAR(30) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(50) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_name VARCHAR(30) NOT NULL,
+    permission_name VARCHAR(50) NOT NULL,
+    PRIMARY KEY (role_name, permission_name),
+    FOREIGN KEY (role_name) REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (permission_name) REFERENCES permissions(name) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('products:write', 'Create, update and delete products'),
+    ('orders:read_all', 'Read the orders of every user'),
+    ('orders:update_status', 'Move orders through their statuses'),
+    ('users:read', 'Read user accounts'),
+    ('users:write', 'Manage user accounts and assign roles'),
+    ('roles:manage', 'Create, update and delete roles');
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access'),
+    ('user', 'Customer'),
+    ('baker', 'Manages the catalogue and prepares orders'),
+    ('cashier', 'Handles orders at the counter'),
+    ('support', 'Helps customers with their orders and accounts');
+
+INSERT INTO role_permissions (role_name, permission_name)
+    SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('baker', 'products:write'),
+    ('baker', 'orders:read_all'),
+    ('baker', 'orders:update_status'),
+    ('cashier', 'orders:read_all'),
+    ('cashier', 'orders:update_status'),
+    ('support', 'orders:read_all'),
+    ('support', 'users:read');
+
+ALTER TABLE users MODIFY role VARCHAR(30) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+COMMIT;
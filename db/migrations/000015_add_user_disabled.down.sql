@@ -0,0 +1,13 @@
+BEGIN;
+
+-- idx_users_role replaced the index MySQL created for fk_users_role, so the foreign key has to be
+-- dropped before the index can go and is added back with its own index afterwards
+ALTER TABLE users DROP FOREIGN KEY fk_users_role;
+
+DROP INDEX idx_users_role ON users;
+
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+ALTER TABLE users DROP COLUMN disabled_at;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP NULL AFTER totp_last_counter;
+
+CREATE INDEX idx_users_role ON users (role);
+
+COMMIT;
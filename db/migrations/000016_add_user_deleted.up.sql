@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Deleted accounts are anonymized rather than removed so their orders stay on the books
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL AFTER disabled_at;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE products DROP FOREIGN KEY fk_products_category;
+ALTER TABLE products DROP COLUMN category_id;
+
+DROP TABLE product_tags;
+DROP TABLE tags;
+DROP TABLE categories;
+
+COMMIT;
@@ -0,0 +1,42 @@
+BEGIN;
+
+CREATE TABLE categories (
+    id VARCHAR(36) PRIMARY KEY,
+    parent_id VARCHAR(36) NULL,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(120) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    -- Subcategories have to be moved or removed before their parent
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE tags (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    slug VARCHAR(60) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE product_tags (
+    product_id VARCHAR(36) NOT NULL,
+    tag_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (product_id, tag_id),
+    INDEX idx_product_tags_tag (tag_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- Products outlive their category, they are simply uncategorized once it is deleted
+ALTER TABLE products ADD COLUMN category_id VARCHAR(36) NULL AFTER id;
+ALTER TABLE products ADD CONSTRAINT fk_products_category FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL;
+
+INSERT INTO categories (id, name, slug) VALUES
+    (UUID(), 'Breads', 'breads'),
+    (UUID(), 'Cakes', 'cakes'),
+    (UUID(), 'Pastries', 'pastries'),
+    (UUID(), 'Seasonal', 'seasonal');
+
+COMMIT;
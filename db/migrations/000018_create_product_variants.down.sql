@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE order_item_options;
+
+ALTER TABLE order_items DROP FOREIGN KEY fk_order_items_variant;
+ALTER TABLE order_items DROP COLUMN variant_name, DROP COLUMN variant_id;
+
+DROP TABLE product_options;
+DROP TABLE product_option_groups;
+DROP TABLE product_variants;
+
+COMMIT;
@@ -0,0 +1,57 @@
+BEGIN;
+
+-- Variants are the sellable versions of a product, e.g. a 6", 8" or 10" cake, each with its own price and stock
+CREATE TABLE product_variants (
+    id VARCHAR(36) PRIMARY KEY,
+    product_id VARCHAR(36) NOT NULL,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    price_delta DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    stock INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_product_variants_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- Option groups hold add-ons such as candles or a custom message, max_select 0 means no limit
+CREATE TABLE product_option_groups (
+    id VARCHAR(36) PRIMARY KEY,
+    product_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    max_select INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_product_option_groups_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE product_options (
+    id VARCHAR(36) PRIMARY KEY,
+    group_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (group_id) REFERENCES product_option_groups(id) ON DELETE CASCADE
+);
+
+-- Order lines keep a snapshot of the variant and options they were sold with
+ALTER TABLE order_items
+    ADD COLUMN variant_id VARCHAR(36) NULL AFTER product_name,
+    ADD COLUMN variant_name VARCHAR(100) NOT NULL DEFAULT '' AFTER variant_id,
+    ADD CONSTRAINT fk_order_items_variant FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
+
+CREATE TABLE order_item_options (
+    id VARCHAR(36) PRIMARY KEY,
+    order_item_id VARCHAR(36) NOT NULL,
+    option_id VARCHAR(36) NULL,
+    group_name VARCHAR(100) NOT NULL,
+    option_name VARCHAR(100) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES product_options(id) ON DELETE SET NULL
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX idx_products_created_at ON products;
+DROP INDEX idx_products_price ON products;
+DROP INDEX ft_products_name_description ON products;
+
+COMMIT;
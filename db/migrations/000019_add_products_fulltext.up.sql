@@ -0,0 +1,7 @@
+BEGIN;
+
+CREATE FULLTEXT INDEX ft_products_name_description ON products (name, description);
+CREATE INDEX idx_products_price ON products (price);
+CREATE INDEX idx_products_created_at ON products (created_at);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Soft deleted and archived products are kept and become visible again without the columns,
+-- purge them before rolling back if they should not come back
+ALTER TABLE products
+    DROP INDEX idx_products_visibility,
+    DROP COLUMN deleted_at,
+    DROP COLUMN is_active;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Deleted products stay in the table so order history keeps pointing at them,
+-- inactive products are hidden from the catalog without being deleted
+ALTER TABLE products
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE AFTER image,
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER is_active,
+    ADD INDEX idx_products_visibility (deleted_at, is_active);
+
+COMMIT;
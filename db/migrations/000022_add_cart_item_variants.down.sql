@@ -0,0 +1,30 @@
+BEGIN;
+
+DROP TABLE IF EXISTS cart_item_options;
+
+-- Lines of a product that only differed by variant or add-ons are merged into one, keeping their quantity
+UPDATE cart_items ci
+JOIN (
+    SELECT MIN(id) AS id, SUM(quantity) AS quantity
+    FROM cart_items
+    GROUP BY cart_id, product_id
+    HAVING COUNT(*) > 1
+) merged ON merged.id = ci.id
+SET ci.quantity = merged.quantity;
+
+DELETE ci FROM cart_items ci
+JOIN (
+    SELECT cart_id, product_id, MIN(id) AS id
+    FROM cart_items
+    GROUP BY cart_id, product_id
+) kept ON kept.cart_id = ci.cart_id AND kept.product_id = ci.product_id AND kept.id <> ci.id;
+
+ALTER TABLE cart_items DROP FOREIGN KEY fk_cart_items_variant;
+
+ALTER TABLE cart_items
+    ADD UNIQUE KEY uq_cart_items_cart_product (cart_id, product_id),
+    DROP INDEX uq_cart_items_line,
+    DROP COLUMN line_key,
+    DROP COLUMN variant_id;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Cart lines carry the variant and add-ons they will be ordered with. line_key hashes the variant
+-- and the sorted option ids, so the same product with another variant or other add-ons is its own line
+ALTER TABLE cart_items
+    ADD COLUMN variant_id VARCHAR(36) NULL AFTER product_id,
+    ADD COLUMN line_key CHAR(64) NOT NULL DEFAULT '' AFTER variant_id,
+    ADD CONSTRAINT fk_cart_items_variant FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE,
+    ADD UNIQUE KEY uq_cart_items_line (cart_id, product_id, line_key),
+    DROP INDEX uq_cart_items_cart_product;
+
+-- option_id has no foreign key so a removed add-on shows up as an invalid selection in the cart
+-- instead of silently disappearing from the line
+CREATE TABLE cart_item_options (
+    cart_item_id VARCHAR(36) NOT NULL,
+    option_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (cart_item_id, option_id),
+    FOREIGN KEY (cart_item_id) REFERENCES cart_items(id) ON DELETE CASCADE
+);
+
+COMMIT;
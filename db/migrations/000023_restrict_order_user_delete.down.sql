@@ -0,0 +1,9 @@
+BEGIN;
+
+-- The original name comes back but the constraint keeps RESTRICT, a cascade would let a user
+-- deletion wipe order history
+ALTER TABLE orders DROP FOREIGN KEY fk_orders_user;
+
+ALTER TABLE orders ADD CONSTRAINT orders_ibfk_1 FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+COMMIT;
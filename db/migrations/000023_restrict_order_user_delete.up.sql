@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Deleting a user must never take their orders along, the service refuses users with orders and
+-- the database now backs that up instead of cascading
+ALTER TABLE orders DROP FOREIGN KEY orders_ibfk_1;
+
+ALTER TABLE orders ADD CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE RESTRICT;
+
+COMMIT;
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/savioruz/bake/internal/handler"
+	e "github.com/savioruz/bake/pkg/error"
 	"github.com/savioruz/bake/pkg/middleware"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
@@ -17,14 +18,28 @@ type Routes struct {
 	Method  string
 	Path    string
 	Handler http.HandlerFunc
-	Roles   []string
+	// Roles and Permissions restrict private routes, a caller needs one of the roles and every permission
+	Roles       []string
+	Permissions []string
+	// Idempotent routes replay the stored response for retries sent with an Idempotency-Key header
+	Idempotent bool
 }
 
 type Config struct {
-	AuthMiddleware *middleware.AuthMiddleware
-	UserHandler    *handler.UserHandler
-	ProductHandler *handler.ProductHandler
-	OrderHandler   *handler.OrderHandler
+	AuthMiddleware        *middleware.AuthMiddleware
+	IdempotencyMiddleware *middleware.IdempotencyMiddleware
+	UserHandler           *handler.UserHandler
+	ProductHandler        *handler.ProductHandler
+	OrderHandler          *handler.OrderHandler
+	CartHandler           *handler.CartHandler
+	AddressHandler        *handler.AddressHandler
+	PaymentHandler        *handler.PaymentHandler
+	RoleHandler           *handler.RoleHandler
+	CategoryHandler       *handler.CategoryHandler
+	TagHandler            *handler.TagHandler
+	VariantHandler        *handler.VariantHandler
+	ProductImageHandler   *handler.ProductImageHandler
+	JWKSHandler           *handler.JWKSHandler
 }
 
 // Helper function to prefix routes with /api/v1
@@ -33,32 +48,58 @@ func prefixRoute(path string) string {
 }
 
 func PublicRoutes(c *Config) []Routes {
-	return []Routes{
+	return idempotent(c, []Routes{
 		{
 			Method: http.MethodGet,
-			Path:   "/",
+			Path:   prefixRoute("/{$}"),
 			Handler: func(w http.ResponseWriter, r *http.Request) {
 				w.Write([]byte("Hello World"))
 			},
 		},
 		{
-			Method:  http.MethodPost,
-			Path:    prefixRoute("/users"),
-			Handler: c.UserHandler.Register,
+			Method:  http.MethodGet,
+			Path:    "/.well-known/jwks.json",
+			Handler: c.JWKSHandler.Get,
+		},
+		{
+			Method:     http.MethodPost,
+			Path:       prefixRoute("/users"),
+			Handler:    c.UserHandler.Register,
+			Idempotent: true,
 		},
 		{
 			Method:  http.MethodPost,
 			Path:    prefixRoute("/users/login"),
 			Handler: c.UserHandler.Login,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/login/2fa"),
+			Handler: c.UserHandler.LoginMFA,
+		},
 		{
 			Method:  http.MethodPost,
 			Path:    prefixRoute("/users/refresh"),
 			Handler: c.UserHandler.RefreshToken,
 		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/password/forgot"),
+			Handler: c.UserHandler.ForgotPassword,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/password/reset"),
+			Handler: c.UserHandler.ResetPassword,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/users/verify-email"),
+			Handler: c.UserHandler.VerifyEmail,
+		},
 		{
 			Method: http.MethodGet,
-			Path:   prefixRoute("/docs/*"),
+			Path:   prefixRoute("/docs/"),
 			Handler: httpSwagger.Handler(
 				httpSwagger.URL(prefixRoute("/docs/doc.json")),
 			),
@@ -78,48 +119,391 @@ func PublicRoutes(c *Config) []Routes {
 			Path:    prefixRoute("/products/{id}"),
 			Handler: c.ProductHandler.GetByID,
 		},
-	}
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/products/{id}/options"),
+			Handler: c.VariantHandler.GetOptions,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/categories"),
+			Handler: c.CategoryHandler.GetAll,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/categories/{slug}/products"),
+			Handler: c.ProductHandler.GetByCategory,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/tags"),
+			Handler: c.TagHandler.GetAll,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/payments/webhook"),
+			Handler: c.PaymentHandler.Webhook,
+		},
+	})
 }
 
 func PrivateRoutes(c *Config) []Routes {
-	return []Routes{
+	return authorize(c, idempotent(c, []Routes{
 		{
 			Method:  http.MethodGet,
 			Path:    prefixRoute("/users/me"),
-			Handler: c.AuthMiddleware.RequireAuth(c.UserHandler.Me),
-			Roles:   []string{"user", "admin"},
+			Handler: c.UserHandler.Me,
+		},
+		{
+			Method:  http.MethodPatch,
+			Path:    prefixRoute("/users/me"),
+			Handler: c.UserHandler.UpdateProfile,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    prefixRoute("/users/me"),
+			Handler: c.UserHandler.DeleteAccount,
 		},
 		{
 			Method:  http.MethodPost,
-			Path:    prefixRoute("/products"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin"}, c.ProductHandler.Create),
+			Path:    prefixRoute("/users/me/password"),
+			Handler: c.UserHandler.ChangePassword,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/logout"),
+			Handler: c.UserHandler.Logout,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/logout-all"),
+			Handler: c.UserHandler.LogoutAll,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/verify-email"),
+			Handler: c.UserHandler.ResendVerification,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/2fa/enroll"),
+			Handler: c.UserHandler.EnrollTOTP,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/2fa/confirm"),
+			Handler: c.UserHandler.ConfirmTOTP,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/2fa/disable"),
+			Handler: c.UserHandler.DisableTOTP,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/users/me/addresses"),
+			Handler: c.AddressHandler.GetAll,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/addresses"),
+			Handler: c.AddressHandler.Create,
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/users/me/addresses/{id}"),
+			Handler: c.AddressHandler.GetByID,
 		},
 		{
 			Method:  http.MethodPut,
-			Path:    prefixRoute("/products/{id}"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin"}, c.ProductHandler.Update),
+			Path:    prefixRoute("/users/me/addresses/{id}"),
+			Handler: c.AddressHandler.Update,
 		},
 		{
 			Method:  http.MethodDelete,
-			Path:    prefixRoute("/products/{id}"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin"}, c.ProductHandler.Delete),
+			Path:    prefixRoute("/users/me/addresses/{id}"),
+			Handler: c.AddressHandler.Delete,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/users/me/addresses/{id}/default"),
+			Handler: c.AddressHandler.SetDefault,
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/products"),
+			Handler:     c.ProductHandler.Create,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/products/{id}"),
+			Handler:     c.ProductHandler.Update,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/products/{id}"),
+			Handler:     c.ProductHandler.Delete,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        prefixRoute("/products/archived"),
+			Handler:     c.ProductHandler.GetArchived,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/products/{id}/restore"),
+			Handler:     c.ProductHandler.Restore,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/products/{id}/purge"),
+			Handler:     c.ProductHandler.Purge,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/products/{id}/variants"),
+			Handler:     c.VariantHandler.CreateVariant,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/products/{id}/variants/{variant_id}"),
+			Handler:     c.VariantHandler.UpdateVariant,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/products/{id}/variants/{variant_id}"),
+			Handler:     c.VariantHandler.DeleteVariant,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/products/{id}/option-groups"),
+			Handler:     c.VariantHandler.CreateOptionGroup,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/products/{id}/option-groups/{group_id}"),
+			Handler:     c.VariantHandler.UpdateOptionGroup,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/products/{id}/option-groups/{group_id}"),
+			Handler:     c.VariantHandler.DeleteOptionGroup,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/products/{id}/images"),
+			Handler:     c.ProductImageHandler.Upload,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/products/{id}/images/order"),
+			Handler:     c.ProductImageHandler.Reorder,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/products/{id}/images/{image_id}"),
+			Handler:     c.ProductImageHandler.Delete,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/categories"),
+			Handler:     c.CategoryHandler.Create,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/categories/{id}"),
+			Handler:     c.CategoryHandler.Update,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/categories/{id}"),
+			Handler:     c.CategoryHandler.Delete,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPost,
+			Path:        prefixRoute("/tags"),
+			Handler:     c.TagHandler.Create,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/tags/{id}"),
+			Handler:     c.TagHandler.Update,
+			Permissions: []string{middleware.PermissionProductsWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/tags/{id}"),
+			Handler:     c.TagHandler.Delete,
+			Permissions: []string{middleware.PermissionProductsWrite},
 		},
 		{
 			Method:  http.MethodGet,
 			Path:    prefixRoute("/orders"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin", "user"}, c.OrderHandler.GetAll),
+			Handler: c.OrderHandler.GetAll,
 		},
 		{
 			Method:  http.MethodGet,
 			Path:    prefixRoute("/orders/{id}"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin", "user"}, c.OrderHandler.GetByID),
+			Handler: c.OrderHandler.GetByID,
+		},
+		{
+			Method:     http.MethodPost,
+			Path:       prefixRoute("/orders"),
+			Handler:    c.OrderHandler.Create,
+			Idempotent: true,
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        prefixRoute("/orders/{id}/status"),
+			Handler:     c.OrderHandler.UpdateStatus,
+			Permissions: []string{middleware.PermissionOrdersUpdateStatus},
 		},
 		{
 			Method:  http.MethodPost,
-			Path:    prefixRoute("/orders"),
-			Handler: c.AuthMiddleware.RequireRole([]string{"admin", "user"}, c.OrderHandler.Create),
+			Path:    prefixRoute("/orders/{id}/cancel"),
+			Handler: c.OrderHandler.Cancel,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/orders/{id}/payments"),
+			Handler: c.PaymentHandler.Create,
 		},
+		{
+			Method:  http.MethodGet,
+			Path:    prefixRoute("/cart"),
+			Handler: c.CartHandler.Get,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    prefixRoute("/cart"),
+			Handler: c.CartHandler.Clear,
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/cart/items"),
+			Handler: c.CartHandler.AddItem,
+		},
+		{
+			Method:  http.MethodPut,
+			Path:    prefixRoute("/cart/items/{id}"),
+			Handler: c.CartHandler.UpdateItem,
+		},
+		{
+			Method:  http.MethodDelete,
+			Path:    prefixRoute("/cart/items/{id}"),
+			Handler: c.CartHandler.RemoveItem,
+		},
+		{
+			Method:     http.MethodPost,
+			Path:       prefixRoute("/cart/checkout"),
+			Handler:    c.CartHandler.Checkout,
+			Idempotent: true,
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        prefixRoute("/users"),
+			Handler:     c.UserHandler.GetAll,
+			Permissions: []string{middleware.PermissionUsersRead},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        prefixRoute("/users/{id}"),
+			Handler:     c.UserHandler.GetByID,
+			Permissions: []string{middleware.PermissionUsersRead},
+		},
+		{
+			Method:      http.MethodPatch,
+			Path:        prefixRoute("/users/{id}"),
+			Handler:     c.UserHandler.Update,
+			Permissions: []string{middleware.PermissionUsersWrite},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/users/{id}"),
+			Handler:     c.UserHandler.Delete,
+			Permissions: []string{middleware.PermissionUsersWrite},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/users/{id}/role"),
+			Handler:     c.UserHandler.AssignRole,
+			Permissions: []string{middleware.PermissionUsersWrite},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        prefixRoute("/roles"),
+			Handler:     c.RoleHandler.GetAll,
+			Permissions: []string{middleware.PermissionRolesManage},
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        prefixRoute("/roles/{name}"),
+			Handler:     c.RoleHandler.Save,
+			Permissions: []string{middleware.PermissionRolesManage},
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        prefixRoute("/roles/{name}"),
+			Handler:     c.RoleHandler.Delete,
+			Permissions: []string{middleware.PermissionRolesManage},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        prefixRoute("/permissions"),
+			Handler:     c.RoleHandler.GetPermissions,
+			Permissions: []string{middleware.PermissionRolesManage},
+		},
+	}))
+}
+
+// SandboxRoutes lets order owners settle their own payments, they are only registered when payment
+// simulation is explicitly enabled for development against the sandbox gateway
+func SandboxRoutes(c *Config) []Routes {
+	return authorize(c, []Routes{
+		{
+			Method:  http.MethodPost,
+			Path:    prefixRoute("/payments/{id}/simulate"),
+			Handler: c.PaymentHandler.Simulate,
+		},
+	})
+}
+
+// idempotent wraps the routes marked Idempotent, authorize has to run after it so the
+// idempotency check ends up inside authentication
+func idempotent(c *Config, routes []Routes) []Routes {
+	for i := range routes {
+		if routes[i].Idempotent {
+			routes[i].Handler = c.IdempotencyMiddleware.Handle(routes[i].Handler)
+		}
+	}
+	return routes
+}
+
+// authorize puts every private route behind authentication, narrowed down by its Roles and Permissions
+func authorize(c *Config, routes []Routes) []Routes {
+	for i := range routes {
+		routes[i].Handler = c.AuthMiddleware.Authorize(routes[i].Roles, routes[i].Permissions, routes[i].Handler)
 	}
+	return routes
 }
 
 func SwaggerRoutes() []Routes {
@@ -135,3 +519,23 @@ func SwaggerRoutes() []Routes {
 		},
 	}
 }
+
+// UploadRoutes serves the files of the local blob store, directory listings are not exposed
+func UploadRoutes(dir string) []Routes {
+	files := http.StripPrefix("/uploads/", http.FileServer(http.Dir(dir)))
+
+	return []Routes{
+		{
+			Method: http.MethodGet,
+			Path:   "/uploads/{path...}",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/") {
+					e.ErrorHandler(w, r, http.StatusNotFound, e.ErrNotFound)
+					return
+				}
+				w.Header().Set("X-Content-Type-Options", "nosniff")
+				files.ServeHTTP(w, r)
+			},
+		},
+	}
+}
@@ -10,6 +10,7 @@ type Address struct {
 	State       string    `db:"state" json:"state"`
 	PostalCode  string    `db:"postal_code" json:"postal_code"`
 	Country     string    `db:"country" json:"country"`
+	IsDefault   bool      `db:"is_default" json:"is_default"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 	User        *User     `db:"-" json:"user,omitempty"`
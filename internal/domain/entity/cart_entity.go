@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type Cart struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type CartItem struct {
+	ID        string  `db:"id"`
+	CartID    string  `db:"cart_id"`
+	ProductID string  `db:"product_id"`
+	VariantID *string `db:"variant_id"`
+	// LineKey tells lines of the same product apart by their variant and add-ons
+	LineKey   string    `db:"line_key"`
+	Quantity  int       `db:"quantity"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	OptionIDs []string  `db:"-"`
+}
+
+// CartItemOption is an add-on chosen for a cart line
+type CartItemOption struct {
+	CartItemID string `db:"cart_item_id"`
+	OptionID   string `db:"option_id"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Category struct {
+	ID          string    `db:"id" json:"id"`
+	ParentID    *string   `db:"parent_id" json:"parent_id"`
+	Name        string    `db:"name" json:"name"`
+	Slug        string    `db:"slug" json:"slug"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+)
+
+// IdempotencyKey is a stored response for a request sent with an Idempotency-Key header,
+// StatusCode stays NULL while the first request is still being handled
+type IdempotencyKey struct {
+	KeyHash      string         `db:"key_hash"`
+	Fingerprint  string         `db:"fingerprint"`
+	StatusCode   sql.NullInt64  `db:"status_code"`
+	ContentType  sql.NullString `db:"content_type"`
+	ResponseBody []byte         `db:"response_body"`
+	ExpiresAt    time.Time      `db:"expires_at"`
+	CreatedAt    time.Time      `db:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at"`
+}
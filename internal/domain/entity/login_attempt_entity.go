@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type LoginAttempt struct {
+	AttemptKey   string     `db:"attempt_key"`
+	Failures     int        `db:"failures"`
+	LockedUntil  *time.Time `db:"locked_until"`
+	LastFailedAt time.Time  `db:"last_failed_at"`
+}
@@ -3,13 +3,17 @@ package entity
 import "time"
 
 type Order struct {
-	ID         string    `db:"id"`
-	UserID     string    `db:"user_id"`
-	ProductID  string    `db:"product_id"`
-	AddressID  string    `db:"address_id"`
-	Quantity   int       `db:"quantity"`
-	TotalPrice float64   `db:"total_price"`
-	Status     string    `db:"status"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID                  string      `db:"id"`
+	UserID              string      `db:"user_id"`
+	AddressID           *string     `db:"address_id"`
+	ShippingAddressLine string      `db:"shipping_address_line"`
+	ShippingCity        string      `db:"shipping_city"`
+	ShippingState       string      `db:"shipping_state"`
+	ShippingPostalCode  string      `db:"shipping_postal_code"`
+	ShippingCountry     string      `db:"shipping_country"`
+	TotalPrice          float64     `db:"total_price"`
+	Status              string      `db:"status"`
+	CreatedAt           time.Time   `db:"created_at"`
+	UpdatedAt           time.Time   `db:"updated_at"`
+	Items               []OrderItem `db:"-"`
 }
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+type OrderItem struct {
+	ID          string            `db:"id"`
+	OrderID     string            `db:"order_id"`
+	ProductID   string            `db:"product_id"`
+	ProductName string            `db:"product_name"`
+	VariantID   *string           `db:"variant_id"`
+	VariantName string            `db:"variant_name"`
+	Quantity    int               `db:"quantity"`
+	UnitPrice   float64           `db:"unit_price"`
+	Subtotal    float64           `db:"subtotal"`
+	CreatedAt   time.Time         `db:"created_at"`
+	UpdatedAt   time.Time         `db:"updated_at"`
+	Options     []OrderItemOption `db:"-"`
+}
+
+// OrderItemOption is an add-on as it was sold with an order line
+type OrderItemOption struct {
+	ID          string  `db:"id"`
+	OrderItemID string  `db:"order_item_id"`
+	OptionID    *string `db:"option_id"`
+	GroupName   string  `db:"group_name"`
+	OptionName  string  `db:"option_name"`
+	Price       float64 `db:"price"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type Payment struct {
+	ID        string    `db:"id"`
+	OrderID   string    `db:"order_id"`
+	Gateway   string    `db:"gateway"`
+	IntentID  string    `db:"intent_id"`
+	Amount    float64   `db:"amount"`
+	Currency  string    `db:"currency"`
+	Status    string    `db:"status"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type PaymentEvent struct {
+	ID        string    `db:"id"`
+	Gateway   string    `db:"gateway"`
+	Type      string    `db:"type"`
+	IntentID  string    `db:"intent_id"`
+	Payload   string    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -3,12 +3,20 @@ package entity
 import "time"
 
 type Product struct {
-	ID          string    `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	Price       float64   `db:"price" json:"price"`
-	Stock       int       `db:"stock" json:"stock"`
-	Image       string    `db:"image" json:"image"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          string     `db:"id" json:"id"`
+	CategoryID  *string    `db:"category_id" json:"category_id"`
+	Name        string     `db:"name" json:"name"`
+	Description string     `db:"description" json:"description"`
+	Price       float64    `db:"price" json:"price"`
+	Stock       int        `db:"stock" json:"stock"`
+	Image       string     `db:"image" json:"image"`
+	IsActive    bool       `db:"is_active" json:"is_active"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"deleted_at"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Available reports whether customers can see and buy the product
+func (p *Product) Available() bool {
+	return p.IsActive && p.DeletedAt == nil
 }
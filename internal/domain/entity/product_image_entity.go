@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type ProductImage struct {
+	ID           string    `db:"id" json:"id"`
+	ProductID    string    `db:"product_id" json:"product_id"`
+	ObjectKey    string    `db:"object_key" json:"object_key"`
+	ThumbnailKey string    `db:"thumbnail_key" json:"thumbnail_key"`
+	ContentType  string    `db:"content_type" json:"content_type"`
+	Size         int       `db:"size" json:"size"`
+	Width        int       `db:"width" json:"width"`
+	Height       int       `db:"height" json:"height"`
+	Position     int       `db:"position" json:"position"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (ProductImage) TableName() string {
+	return "product_images"
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+type ProductVariant struct {
+	ID         string    `db:"id" json:"id"`
+	ProductID  string    `db:"product_id" json:"product_id"`
+	SKU        string    `db:"sku" json:"sku"`
+	Name       string    `db:"name" json:"name"`
+	PriceDelta float64   `db:"price_delta" json:"price_delta"`
+	Stock      int       `db:"stock" json:"stock"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (ProductVariant) TableName() string {
+	return "product_variants"
+}
+
+type ProductOptionGroup struct {
+	ID        string    `db:"id" json:"id"`
+	ProductID string    `db:"product_id" json:"product_id"`
+	Name      string    `db:"name" json:"name"`
+	Required  bool      `db:"required" json:"required"`
+	MaxSelect int       `db:"max_select" json:"max_select"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (ProductOptionGroup) TableName() string {
+	return "product_option_groups"
+}
+
+type ProductOption struct {
+	ID        string    `db:"id" json:"id"`
+	GroupID   string    `db:"group_id" json:"group_id"`
+	Name      string    `db:"name" json:"name"`
+	Price     float64   `db:"price" json:"price"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (ProductOption) TableName() string {
+	return "product_options"
+}
+
+// ProductOptionSelection is an option together with the group and product it belongs to
+type ProductOptionSelection struct {
+	ProductOption
+	ProductID string `db:"product_id" json:"product_id"`
+	GroupName string `db:"group_name" json:"group_name"`
+}
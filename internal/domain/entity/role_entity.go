@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+type Role struct {
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type Permission struct {
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
+type RolePermission struct {
+	RoleName       string `db:"role_name"`
+	PermissionName string `db:"permission_name"`
+}
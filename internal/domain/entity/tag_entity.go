@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+type Tag struct {
+	ID        string    `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// ProductTag is a tag together with the product it is attached to
+type ProductTag struct {
+	ProductID string `db:"product_id" json:"product_id"`
+	Tag
+}
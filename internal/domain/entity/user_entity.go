@@ -3,14 +3,20 @@ package entity
 import "time"
 
 type User struct {
-	ID        string    `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Password  string    `db:"password" json:"password"`
-	Name      string    `db:"name" json:"name"`
-	Phone     string    `db:"phone" json:"phone"`
-	Role      string    `db:"role" json:"role"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID              string     `db:"id" json:"id"`
+	Email           string     `db:"email" json:"email"`
+	Password        string     `db:"password" json:"password"`
+	Name            string     `db:"name" json:"name"`
+	Phone           string     `db:"phone" json:"phone"`
+	Role            string     `db:"role" json:"role"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"email_verified_at"`
+	TOTPSecret      *string    `db:"totp_secret" json:"-"`
+	TOTPEnabledAt   *time.Time `db:"totp_enabled_at" json:"totp_enabled_at"`
+	TOTPLastCounter *int64     `db:"totp_last_counter" json:"-"`
+	DisabledAt      *time.Time `db:"disabled_at" json:"disabled_at"`
+	DeletedAt       *time.Time `db:"deleted_at" json:"deleted_at"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
 }
 
 func (User) TableName() string {
@@ -6,6 +6,21 @@ type AddressRequest struct {
 	State       string `json:"state" validate:"required,min=2,max=50"`
 	PostalCode  string `json:"postal_code" validate:"required,min=5,max=20"`
 	Country     string `json:"country" validate:"required,min=2,max=50"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+type UpdateAddressRequest struct {
+	ID          string  `param:"id" json:"-" validate:"required,uuid"`
+	AddressLine *string `json:"address_line,omitempty" validate:"omitempty,min=5,max=255"`
+	City        *string `json:"city,omitempty" validate:"omitempty,min=2,max=50"`
+	State       *string `json:"state,omitempty" validate:"omitempty,min=2,max=50"`
+	PostalCode  *string `json:"postal_code,omitempty" validate:"omitempty,min=5,max=20"`
+	Country     *string `json:"country,omitempty" validate:"omitempty,min=2,max=50"`
+	IsDefault   *bool   `json:"is_default,omitempty"`
+}
+
+type GetAddressRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
 }
 
 type AddressResponse struct {
@@ -16,6 +31,7 @@ type AddressResponse struct {
 	State       string `json:"state"`
 	PostalCode  string `json:"postal_code"`
 	Country     string `json:"country"`
+	IsDefault   bool   `json:"is_default"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -0,0 +1,52 @@
+package model
+
+type AddCartItemRequest struct {
+	ProductID string   `json:"product_id" validate:"required,uuid"`
+	VariantID string   `json:"variant_id,omitempty" validate:"omitempty,uuid"`
+	OptionIDs []string `json:"option_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	Quantity  int      `json:"quantity" validate:"required,min=1"`
+}
+
+type UpdateCartItemRequest struct {
+	ID       string `param:"id" json:"-" validate:"required,uuid"`
+	Quantity int    `json:"quantity" validate:"required,min=1"`
+}
+
+type DeleteCartItemRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+type CheckoutCartRequest struct {
+	AddressID string `json:"address_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type CartResponse struct {
+	ID          string             `json:"id"`
+	Items       []CartItemResponse `json:"items"`
+	TotalPrice  float64            `json:"total_price"`
+	CanCheckout bool               `json:"can_checkout"`
+	UpdatedAt   string             `json:"updated_at"`
+}
+
+// CartItemResponse prices a line with the live catalog, including its variant and add-ons
+type CartItemResponse struct {
+	ID          string                   `json:"id"`
+	ProductID   string                   `json:"product_id"`
+	Name        string                   `json:"name"`
+	Image       string                   `json:"image"`
+	VariantID   *string                  `json:"variant_id,omitempty"`
+	VariantName string                   `json:"variant_name,omitempty"`
+	Options     []CartItemOptionResponse `json:"options,omitempty"`
+	Quantity    int                      `json:"quantity"`
+	UnitPrice   float64                  `json:"unit_price"`
+	Subtotal    float64                  `json:"subtotal"`
+	Stock       int                      `json:"stock"`
+	Warning     string                   `json:"warning,omitempty"`
+}
+
+type CartItemOptionResponse struct {
+	OptionID   string  `json:"option_id"`
+	GroupName  string  `json:"group_name"`
+	OptionName string  `json:"option_name"`
+	Price      float64 `json:"price"`
+}
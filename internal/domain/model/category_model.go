@@ -0,0 +1,33 @@
+package model
+
+type CategoryResponse struct {
+	ID          string              `json:"id"`
+	ParentID    *string             `json:"parent_id"`
+	Name        string              `json:"name"`
+	Slug        string              `json:"slug"`
+	Description string              `json:"description"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+	Children    []*CategoryResponse `json:"children,omitempty"`
+}
+
+// CreateCategoryRequest creates a category, the slug is derived from the name when it is left out
+type CreateCategoryRequest struct {
+	ParentID    *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	Name        string  `json:"name" validate:"required,min=2,max=100"`
+	Slug        string  `json:"slug,omitempty" validate:"omitempty,max=120"`
+	Description string  `json:"description" validate:"max=255"`
+}
+
+// UpdateCategoryRequest changes a category, an empty parent_id moves it to the top level
+type UpdateCategoryRequest struct {
+	ID          string  `param:"id" json:"-" validate:"required,uuid"`
+	ParentID    *string `json:"parent_id,omitempty" validate:"omitempty,eq=|uuid"`
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Slug        *string `json:"slug,omitempty" validate:"omitempty,max=120"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+type GetCategoryRequest struct {
+	ID string `param:"id" json:"id" validate:"required,uuid"`
+}
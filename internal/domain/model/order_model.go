@@ -1,34 +1,69 @@
 package model
 
-import "github.com/savioruz/bake/internal/domain/entity"
-
 type CreateOrderRequest struct {
-	UserID    string `json:"user_id" validate:"required,uuid"`
-	ProductID string `json:"product_id" validate:"required,uuid"`
-	Quantity  int    `json:"quantity" validate:"required,min=1"`
+	AddressID string             `json:"address_id,omitempty" validate:"omitempty,uuid"`
+	Items     []OrderItemRequest `json:"items" validate:"required,min=1,max=50,dive"`
+}
+
+type OrderItemRequest struct {
+	ProductID string   `json:"product_id" validate:"required,uuid"`
+	VariantID string   `json:"variant_id,omitempty" validate:"omitempty,uuid"`
+	OptionIDs []string `json:"option_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	Quantity  int      `json:"quantity" validate:"required,min=1"`
 }
 
 type OrderResponse struct {
-	ID         string         `json:"id"`
-	UserID     string         `json:"user_id"`
-	ProductID  string         `json:"product_id"`
-	AddressID  string         `json:"address_id"`
-	Quantity   int            `json:"quantity"`
-	TotalPrice float64        `json:"total_price"`
-	Status     string         `json:"status"`
-	CreatedAt  string         `json:"created_at"`
-	UpdatedAt  string         `json:"updated_at"`
-	Product    entity.Product `json:"product"`
-	Address    entity.Address `json:"address"`
+	ID              string                  `json:"id"`
+	UserID          string                  `json:"user_id"`
+	AddressID       *string                 `json:"address_id,omitempty"`
+	Items           []OrderItemResponse     `json:"items"`
+	TotalPrice      float64                 `json:"total_price"`
+	Status          string                  `json:"status"`
+	CreatedAt       string                  `json:"created_at"`
+	UpdatedAt       string                  `json:"updated_at"`
+	ShippingAddress ShippingAddressResponse `json:"shipping_address"`
+}
+
+// OrderItemResponse renders the product as it was sold, not as it is in the catalog now
+type OrderItemResponse struct {
+	ID          string                    `json:"id"`
+	ProductID   string                    `json:"product_id"`
+	ProductName string                    `json:"product_name"`
+	VariantID   *string                   `json:"variant_id,omitempty"`
+	VariantName string                    `json:"variant_name,omitempty"`
+	Options     []OrderItemOptionResponse `json:"options,omitempty"`
+	Quantity    int                       `json:"quantity"`
+	UnitPrice   float64                   `json:"unit_price"`
+	Subtotal    float64                   `json:"subtotal"`
+}
+
+type OrderItemOptionResponse struct {
+	GroupName  string  `json:"group_name"`
+	OptionName string  `json:"option_name"`
+	Price      float64 `json:"price"`
+}
+
+// ShippingAddressResponse renders the address an order was placed with
+type ShippingAddressResponse struct {
+	AddressLine string `json:"address_line"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	PostalCode  string `json:"postal_code"`
+	Country     string `json:"country"`
 }
 
 type OrderPagination struct {
 	Page  int    `query:"page,omitempty" validate:"omitempty,min=1"`
 	Limit int    `query:"limit,omitempty" validate:"omitempty,min=1,max=100"`
-	Sort  string `query:"sort,omitempty" validate:"omitempty,oneof=id user_id product_id address_id quantity total_price status created_at updated_at ID USER_ID PRODUCT_ID ADDRESS_ID QUANTITY TOTAL_PRICE STATUS CREATED_AT UPDATED_AT"`
+	Sort  string `query:"sort,omitempty" validate:"omitempty,oneof=id user_id address_id total_price status created_at updated_at ID USER_ID ADDRESS_ID TOTAL_PRICE STATUS CREATED_AT UPDATED_AT"`
 	Order string `query:"order,omitempty" validate:"omitempty,oneof=ASC DESC asc desc"`
 }
 
 type GetOrderRequest struct {
 	ID string `param:"id" validate:"required,uuid"`
 }
+
+type UpdateOrderStatusRequest struct {
+	ID     string `param:"id" json:"-" validate:"required,uuid"`
+	Status string `json:"status" validate:"required,oneof=PENDING PAID BAKING READY SHIPPED DELIVERED CANCELLED REFUNDED"`
+}
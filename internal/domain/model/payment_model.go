@@ -0,0 +1,29 @@
+package model
+
+type CreatePaymentRequest struct {
+	OrderID string `json:"-" validate:"required,uuid"`
+}
+
+// SimulatePaymentRequest plays the customer's side of a sandbox payment
+type SimulatePaymentRequest struct {
+	ID      string `json:"-" validate:"required,uuid"`
+	Outcome string `json:"outcome" validate:"required,oneof=succeeded failed"`
+}
+
+type PaymentResponse struct {
+	ID           string  `json:"id"`
+	OrderID      string  `json:"order_id"`
+	Gateway      string  `json:"gateway"`
+	IntentID     string  `json:"intent_id"`
+	ClientSecret string  `json:"client_secret,omitempty"`
+	Amount       float64 `json:"amount"`
+	Currency     string  `json:"currency"`
+	Status       string  `json:"status"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+type PaymentWebhookResponse struct {
+	EventID   string `json:"event_id"`
+	Duplicate bool   `json:"duplicate"`
+}
@@ -0,0 +1,33 @@
+package model
+
+type ProductImageResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+}
+
+// UploadProductImagesRequest carries the files of a multipart upload, they are appended after the existing images
+type UploadProductImagesRequest struct {
+	ProductID string          `param:"id" validate:"required,uuid"`
+	Images    []UploadedImage `validate:"required,min=1,max=10,dive"`
+}
+
+type UploadedImage struct {
+	Filename string
+	Data     []byte `validate:"required"`
+}
+
+// ReorderProductImagesRequest lists every image of the product in its new order
+type ReorderProductImagesRequest struct {
+	ProductID string   `param:"id" json:"-" validate:"required,uuid"`
+	ImageIDs  []string `json:"image_ids" validate:"required,min=1,dive,uuid"`
+}
+
+type GetProductImageRequest struct {
+	ProductID string `param:"id" json:"product_id" validate:"required,uuid"`
+	ID        string `param:"image_id" json:"id" validate:"required,uuid"`
+}
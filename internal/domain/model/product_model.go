@@ -3,32 +3,49 @@ package model
 import "time"
 
 type ProductResponse struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	Image       string  `json:"image"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string                 `json:"id"`
+	CategoryID  *string                `json:"category_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price"`
+	Stock       int                    `json:"stock"`
+	Image       string                 `json:"image"`
+	Images      []ProductImageResponse `json:"images"`
+	Tags        []*TagResponse         `json:"tags"`
+	IsActive    bool                   `json:"is_active"`
+	DeletedAt   string                 `json:"deleted_at,omitempty"`
+	CreatedAt   string                 `json:"created_at"`
+	UpdatedAt   string                 `json:"updated_at"`
 }
 
 type ProductQuery struct {
-	ID          *string    `query:"id,omitempty" validate:"omitempty,uuid"`
-	Name        *string    `query:"name,omitempty" validate:"omitempty,min=3,max=255"`
-	Description *string    `query:"description,omitempty" validate:"omitempty,min=3,max=255"`
-	Price       *float64   `query:"price,omitempty" validate:"omitempty,min=0"`
-	Stock       *int       `query:"stock,omitempty" validate:"omitempty,min=0"`
-	Image       *string    `query:"image,omitempty" validate:"omitempty"`
-	CreatedAt   *time.Time `query:"created_at,omitempty" validate:"omitempty,datetime=2006-01-02 15:04:05"`
-	UpdatedAt   *time.Time `query:"updated_at,omitempty" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	ID          *string  `query:"id,omitempty" validate:"omitempty,uuid"`
+	Name        *string  `query:"name,omitempty" validate:"omitempty,min=3,max=255"`
+	Description *string  `query:"description,omitempty" validate:"omitempty,min=3,max=255"`
+	Price       *float64 `query:"price,omitempty" validate:"omitempty,min=0"`
+	Stock       *int     `query:"stock,omitempty" validate:"omitempty,min=0"`
+	Image       *string  `query:"image,omitempty" validate:"omitempty"`
+	// Q searches name and description ranked by relevance
+	Q        *string  `query:"q,omitempty" validate:"omitempty,min=2,max=100"`
+	MinPrice *float64 `query:"min_price,omitempty" validate:"omitempty,min=0"`
+	MaxPrice *float64 `query:"max_price,omitempty" validate:"omitempty,min=0"`
+	InStock  *bool    `query:"in_stock,omitempty"`
+	// CreatedFrom and CreatedTo bound the creation time, both inclusive
+	CreatedFrom *time.Time `query:"created_from,omitempty"`
+	CreatedTo   *time.Time `query:"created_to,omitempty"`
+	// Category matches the category with this slug and its subcategories, Tag matches a tag slug
+	Category *string `query:"category,omitempty" validate:"omitempty,max=120"`
+	Tag      *string `query:"tag,omitempty" validate:"omitempty,max=60"`
 }
 
 type ProductPagination struct {
 	Page  int    `query:"page" default:"1" validate:"numeric,omitempty,min=1"`
 	Limit int    `query:"limit" default:"10" validate:"numeric,omitempty,min=1,max=100"`
-	Sort  string `query:"sort" default:"created_at" validate:"omitempty,oneof=id name description price stock image created_at updated_at"`
+	Sort  string `query:"sort" default:"created_at" validate:"omitempty,oneof=id name description price stock image created_at updated_at relevance"`
 	Order string `query:"order" default:"desc" validate:"omitempty,oneof=asc desc"`
+	// Category matches the category with this slug and its subcategories, Tag matches a tag slug
+	Category string `query:"category" validate:"omitempty,max=120"`
+	Tag      string `query:"tag" validate:"omitempty,max=60"`
 }
 
 type GetProductRequest struct {
@@ -40,7 +57,12 @@ type CreateProductRequest struct {
 	Description string  `json:"description" validate:"required,min=3,max=255"`
 	Price       float64 `json:"price" validate:"required,min=0"`
 	Stock       int     `json:"stock" validate:"required,min=0"`
-	Image       string  `json:"image" validate:"required"`
+	// Image is an external URL, uploaded images are added through /products/{id}/images
+	Image      string   `json:"image,omitempty" validate:"omitempty"`
+	CategoryID *string  `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	TagIDs     []string `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	// IsActive false keeps the product hidden from customers, it defaults to true
+	IsActive *bool `json:"is_active,omitempty"`
 }
 
 type UpdateProductRequest struct {
@@ -49,6 +71,17 @@ type UpdateProductRequest struct {
 	Price       *float64 `json:"price,omitempty" validate:"omitempty,min=0"`
 	Stock       *int     `json:"stock,omitempty" validate:"omitempty,min=0"`
 	Image       *string  `json:"image,omitempty" validate:"omitempty"`
+	// CategoryID moves the product, an empty one leaves it uncategorized
+	CategoryID *string `json:"category_id,omitempty" validate:"omitempty,eq=|uuid"`
+	// TagIDs replaces every tag of the product when it is present
+	TagIDs *[]string `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	// IsActive shows or hides the product from customers
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+// GetCategoryProductsRequest lists the products of the category with this slug and of its subcategories
+type GetCategoryProductsRequest struct {
+	Slug string `param:"slug" validate:"required,max=120"`
 }
 
 type DeleteProductRequest struct {
@@ -0,0 +1,30 @@
+package model
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SaveRoleRequest creates a role or replaces its description and permissions
+type SaveRoleRequest struct {
+	Name        string   `param:"name" json:"-" validate:"required,max=30,lowercase,alphanum"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=50"`
+}
+
+type GetRoleRequest struct {
+	Name string `param:"name" json:"name" validate:"required,max=30"`
+}
+
+type AssignRoleRequest struct {
+	UserID string `param:"id" json:"-" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,max=30"`
+}
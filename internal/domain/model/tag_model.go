@@ -0,0 +1,18 @@
+package model
+
+type TagResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// SaveTagRequest creates or renames a tag, the slug is derived from the name when it is left out
+type SaveTagRequest struct {
+	ID   string `param:"id" json:"-" validate:"omitempty,uuid"`
+	Name string `json:"name" validate:"required,min=2,max=50"`
+	Slug string `json:"slug,omitempty" validate:"omitempty,max=60"`
+}
+
+type GetTagRequest struct {
+	ID string `param:"id" json:"id" validate:"required,uuid"`
+}
@@ -11,17 +11,21 @@ type UserRegisterRequest struct {
 type UserLoginRequest struct {
 	Email    string `json:"email" validate:"required,email,min=3,max=100"`
 	Password string `json:"password" validate:"required,min=8,max=255"`
+	IP       string `json:"-"`
 }
 
 type UserResponse struct {
-	ID        string           `json:"id"`
-	Email     string           `json:"email"`
-	Name      string           `json:"name"`
-	Phone     string           `json:"phone"`
-	Role      string           `json:"role"`
-	CreatedAt string           `json:"created_at"`
-	UpdatedAt string           `json:"updated_at"`
-	Address   *AddressResponse `json:"address,omitempty"`
+	ID            string           `json:"id"`
+	Email         string           `json:"email"`
+	Name          string           `json:"name"`
+	Phone         string           `json:"phone"`
+	Role          string           `json:"role"`
+	EmailVerified bool             `json:"email_verified"`
+	TwoFactor     bool             `json:"two_factor_enabled"`
+	Disabled      bool             `json:"disabled"`
+	CreatedAt     string           `json:"created_at"`
+	UpdatedAt     string           `json:"updated_at"`
+	Address       *AddressResponse `json:"address,omitempty"`
 }
 
 type TokenResponse struct {
@@ -29,6 +33,85 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// LoginResponse holds the tokens, or only a challenge token when the account uses two-factor authentication
+type LoginResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=20"`
+	IP       string `json:"-"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type TOTPEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email,min=3,max=100"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=255"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"-" validate:"required"`
+}
+
+type UserPagination struct {
+	Page  int    `query:"page" validate:"omitempty,min=1"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Sort  string `query:"sort" validate:"omitempty,oneof=email name role created_at updated_at"`
+	Order string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Query string `query:"q" validate:"omitempty,max=100"`
+	Role  string `query:"role" validate:"omitempty,max=30"`
+}
+
+type GetUserRequest struct {
+	ID string `param:"id" json:"id" validate:"required,uuid"`
+}
+
+// UpdateUserRequest changes the role or the disabled flag of a user, absent fields are left alone
+type UpdateUserRequest struct {
+	ID       string  `param:"id" json:"-" validate:"required,uuid"`
+	Role     *string `json:"role,omitempty" validate:"omitempty,max=30"`
+	Disabled *bool   `json:"disabled,omitempty"`
+}
+
+// UpdateProfileRequest changes the profile of the current user, absent fields are left alone
+type UpdateProfileRequest struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=5,max=100"`
+	Phone *string `json:"phone,omitempty" validate:"omitempty,min=10,max=15"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,min=8,max=255"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=255,nefield=CurrentPassword"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required,min=8,max=255"`
+}
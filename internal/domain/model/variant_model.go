@@ -0,0 +1,71 @@
+package model
+
+// VariantResponse renders a variant with its final price, product price plus delta
+type VariantResponse struct {
+	ID         string  `json:"id"`
+	ProductID  string  `json:"product_id"`
+	SKU        string  `json:"sku"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+	Price      float64 `json:"price"`
+	Stock      int     `json:"stock"`
+}
+
+type OptionGroupResponse struct {
+	ID        string           `json:"id"`
+	ProductID string           `json:"product_id"`
+	Name      string           `json:"name"`
+	Required  bool             `json:"required"`
+	MaxSelect int              `json:"max_select"`
+	Options   []OptionResponse `json:"options"`
+}
+
+type OptionResponse struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// ProductOptionsResponse lists everything a customer can choose for a product
+type ProductOptionsResponse struct {
+	ProductID    string                `json:"product_id"`
+	Variants     []VariantResponse     `json:"variants"`
+	OptionGroups []OptionGroupResponse `json:"option_groups"`
+}
+
+// SaveVariantRequest creates a variant, or updates the variant with the given id
+type SaveVariantRequest struct {
+	ProductID  string  `param:"id" json:"-" validate:"required,uuid"`
+	ID         string  `param:"variant_id" json:"-" validate:"omitempty,uuid"`
+	SKU        string  `json:"sku" validate:"required,min=1,max=64"`
+	Name       string  `json:"name" validate:"required,min=1,max=100"`
+	PriceDelta float64 `json:"price_delta"`
+	Stock      int     `json:"stock" validate:"min=0"`
+}
+
+type GetVariantRequest struct {
+	ProductID string `param:"id" json:"product_id" validate:"required,uuid"`
+	ID        string `param:"variant_id" json:"id" validate:"required,uuid"`
+}
+
+// SaveOptionGroupRequest creates or replaces an option group; options with an id are updated,
+// options without one are added and options left out are removed
+type SaveOptionGroupRequest struct {
+	ProductID string              `param:"id" json:"-" validate:"required,uuid"`
+	ID        string              `param:"group_id" json:"-" validate:"omitempty,uuid"`
+	Name      string              `json:"name" validate:"required,min=1,max=100"`
+	Required  bool                `json:"required"`
+	MaxSelect int                 `json:"max_select" validate:"min=0"`
+	Options   []SaveOptionRequest `json:"options" validate:"required,min=1,max=50,dive"`
+}
+
+type SaveOptionRequest struct {
+	ID    string  `json:"id,omitempty" validate:"omitempty,uuid"`
+	Name  string  `json:"name" validate:"required,min=1,max=100"`
+	Price float64 `json:"price" validate:"min=0"`
+}
+
+type GetOptionGroupRequest struct {
+	ProductID string `param:"id" json:"product_id" validate:"required,uuid"`
+	ID        string `param:"group_id" json:"id" validate:"required,uuid"`
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type AddressHandler struct {
+	AddressService *service.AddressService
+	Log            *logrus.Logger
+}
+
+func NewAddressHandler(addressService *service.AddressService, log *logrus.Logger) *AddressHandler {
+	return &AddressHandler{
+		AddressService: addressService,
+		Log:            log,
+	}
+}
+
+// @Summary Get my addresses
+// @Description Get the address book of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[[]model.AddressResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses [get]
+func (h *AddressHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.AddressService.GetAll(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to get addresses: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Get my address by ID
+// @Description Get an address of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} model.SuccessResponse[model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses/{id} [get]
+func (h *AddressHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetAddressRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.AddressService.GetByID(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to get address by id: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Create an address
+// @Description Add an address to the address book of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param address body model.AddressRequest true "Address"
+// @Success 201 {object} model.SuccessResponse[model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses [post]
+func (h *AddressHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.AddressRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := h.AddressService.Create(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to create address: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Update an address
+// @Description Update an address of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param address body model.UpdateAddressRequest true "Address"
+// @Success 200 {object} model.SuccessResponse[model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses/{id} [put]
+func (h *AddressHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.UpdateAddressRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.AddressService.Update(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update address: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Set default address
+// @Description Make an address the default shipping address of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} model.SuccessResponse[model.AddressResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses/{id}/default [post]
+func (h *AddressHandler) SetDefault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetAddressRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.AddressService.SetDefault(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to set default address: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete an address
+// @Description Delete an address of the current user
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} model.SuccessResponse[model.GetAddressRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/addresses/{id} [delete]
+func (h *AddressHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetAddressRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.AddressService.Delete(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete address: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map address errors to responses
+func (h *AddressHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrUnauthorized):
+		e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+	case errors.Is(err, e.ErrAddressNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrAddressInUse):
+		e.ErrorHandler(w, r, http.StatusConflict, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type CartHandler struct {
+	CartService *service.CartService
+	Log         *logrus.Logger
+}
+
+func NewCartHandler(cartService *service.CartService, log *logrus.Logger) *CartHandler {
+	return &CartHandler{
+		CartService: cartService,
+		Log:         log,
+	}
+}
+
+// @Summary Get cart
+// @Description Get the cart of the current user with live prices and stock warnings
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[model.CartResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart [get]
+func (h *CartHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.CartService.Get(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to get cart: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Add item to cart
+// @Description Add a product with an optional variant and add-ons to the cart, increasing the quantity of a line with the same selection
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param item body model.AddCartItemRequest true "Item"
+// @Success 200 {object} model.SuccessResponse[model.CartResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart/items [post]
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.AddCartItemRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := h.CartService.AddItem(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to add cart item: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Update cart item
+// @Description Change the quantity of a cart item
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param id path string true "Cart item ID"
+// @Param item body model.UpdateCartItemRequest true "Item"
+// @Success 200 {object} model.SuccessResponse[model.CartResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart/items/{id} [put]
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.UpdateCartItemRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.CartService.UpdateItem(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update cart item: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Remove cart item
+// @Description Remove an item from the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param id path string true "Cart item ID"
+// @Success 200 {object} model.SuccessResponse[model.CartResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart/items/{id} [delete]
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.DeleteCartItemRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.CartService.RemoveItem(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to remove cart item: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Clear cart
+// @Description Remove every item from the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[model.CartResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart [delete]
+func (h *CartHandler) Clear(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.CartService.Clear(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to clear cart: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Checkout cart
+// @Description Turn the cart into an order and empty the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param checkout body model.CheckoutCartRequest false "Checkout"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe to replay"
+// @Success 201 {object} model.SuccessResponse[model.OrderResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /cart/checkout [post]
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	// The body is optional, an empty one ships to the default address
+	request := &model.CheckoutCartRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil && !errors.Is(err, io.EOF) {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := h.CartService.Checkout(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to checkout cart: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map cart errors to responses
+func (h *CartHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrUnauthorized):
+		e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+	case errors.Is(err, e.ErrNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrCartEmpty), errors.Is(err, e.ErrInsufficientStock), errors.Is(err, e.ErrAddressNotFound),
+		errors.Is(err, e.ErrVariantRequired), errors.Is(err, e.ErrVariantNotFound), errors.Is(err, e.ErrInvalidOptions),
+		errors.Is(err, e.ErrProductUnavailable):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrEmailNotVerified):
+		e.ErrorHandler(w, r, http.StatusForbidden, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
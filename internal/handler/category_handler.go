@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryHandler struct {
+	CategoryService *service.CategoryService
+	Log             *logrus.Logger
+}
+
+func NewCategoryHandler(categoryService *service.CategoryService, log *logrus.Logger) *CategoryHandler {
+	return &CategoryHandler{
+		CategoryService: categoryService,
+		Log:             log,
+	}
+}
+
+// @Summary Get categories
+// @Description Get every category as a tree of subcategories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[[]model.CategoryResponse]
+// @Failure 500 {object} model.ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.CategoryService.GetAll(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to get categories: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Create a category
+// @Description Create a category, optionally nested under a parent
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body model.CreateCategoryRequest true "Category"
+// @Success 201 {object} model.SuccessResponse[model.CategoryResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /categories [post]
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.CreateCategoryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := h.CategoryService.Create(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to create category: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Update a category
+// @Description Rename a category or move it under another parent
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body model.UpdateCategoryRequest true "Category"
+// @Success 200 {object} model.SuccessResponse[model.CategoryResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.UpdateCategoryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.CategoryService.Update(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update category: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete a category
+// @Description Delete a category without subcategories, its products become uncategorized
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} model.SuccessResponse[model.GetCategoryRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetCategoryRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.CategoryService.Delete(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete category: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map category errors to responses
+func (h *CategoryHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation), errors.Is(err, e.ErrInvalidCategoryParent):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrCategoryNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrCategoryInUse), errors.Is(err, e.ErrSlugExists):
+		e.ErrorHandler(w, r, http.StatusConflict, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+type JWKSHandler struct {
+	JWTService jwt.JWTService
+	Log        *logrus.Logger
+}
+
+func NewJWKSHandler(jwtService jwt.JWTService, log *logrus.Logger) *JWKSHandler {
+	return &JWKSHandler{
+		JWTService: jwtService,
+		Log:        log,
+	}
+}
+
+// Get serves the public signing keys at /.well-known/jwks.json, outside of the documented /api/v1 base path
+func (h *JWKSHandler) Get(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.JWTService.JWKS())
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/savioruz/bake/internal/domain/model"
 	"github.com/savioruz/bake/internal/service"
 	e "github.com/savioruz/bake/pkg/error"
-	"github.com/savioruz/bake/pkg/helper"
 	"github.com/sirupsen/logrus"
 )
 
@@ -27,16 +26,17 @@ func NewOrderHandler(orderService *service.OrderService, log *logrus.Logger) *Or
 }
 
 // @Summary Get all orders
-// @Description Get all orders
+// @Description Get all orders of the current user, or every order for admins
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param page query int false "Page"
 // @Param limit query int false "Limit"
-// @Param sort query string false "Sort" Enums(id, user_id, product_id, address_id, quantity, total_price, status, created_at, updated_at)
+// @Param sort query string false "Sort" Enums(id, user_id, address_id, total_price, status, created_at, updated_at)
 // @Param order query string false "Order" Enums(ASC, DESC)
 // @Success 200 {object} model.SuccessResponse[[]model.OrderResponse]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 // @Router /orders [get]
@@ -53,6 +53,8 @@ func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, e.ErrValidation):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
 		}
@@ -72,6 +74,7 @@ func (h *OrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Order ID"
 // @Success 200 {object} model.SuccessResponse[model.OrderResponse]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
 // @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
@@ -83,7 +86,7 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	request := &model.GetOrderRequest{
-		ID: helper.ParseParam(r),
+		ID: r.PathValue("id"),
 	}
 
 	response, err := h.OrderService.GetById(r.Context(), request)
@@ -92,6 +95,8 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, e.ErrValidation):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
 		case errors.Is(err, e.ErrNotFound):
 			e.ErrorHandler(w, r, http.StatusNotFound, err)
 		default:
@@ -111,8 +116,12 @@ func (h *OrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param order body model.CreateOrderRequest true "Order"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe to replay"
 // @Success 201 {object} model.SuccessResponse[model.OrderResponse]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 // @Router /orders [post]
@@ -134,8 +143,16 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, e.ErrValidation):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
-		case errors.Is(err, e.ErrInsufficientStock):
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		case errors.Is(err, e.ErrInsufficientStock), errors.Is(err, e.ErrAddressNotFound),
+			errors.Is(err, e.ErrVariantNotFound), errors.Is(err, e.ErrVariantRequired), errors.Is(err, e.ErrInvalidOptions),
+			errors.Is(err, e.ErrProductUnavailable):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrEmailNotVerified):
+			e.ErrorHandler(w, r, http.StatusForbidden, err)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
 		}
@@ -147,6 +164,101 @@ func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// @Summary Update order status
+// @Description Move an order to the next status of its lifecycle
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param status body model.UpdateOrderStatusRequest true "Status"
+// @Success 200 {object} model.SuccessResponse[model.OrderResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /orders/{id}/status [patch]
+func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.UpdateOrderStatusRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.OrderService.UpdateStatus(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update order status: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		case errors.Is(err, e.ErrInvalidStatusTransition):
+			e.ErrorHandler(w, r, http.StatusConflict, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Cancel an order
+// @Description Cancel an order and return its reserved stock
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} model.SuccessResponse[model.OrderResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetOrderRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.OrderService.Cancel(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to cancel order: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		case errors.Is(err, e.ErrInvalidStatusTransition):
+			e.ErrorHandler(w, r, http.StatusConflict, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // parsePagination is a private helper function to parse pagination parameters
 func (h *OrderHandler) parsePagination(r *http.Request) *model.OrderPagination {
 	pagination := &model.OrderPagination{
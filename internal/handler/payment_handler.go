@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SignatureHeader carries the HMAC signature of a webhook payload
+	SignatureHeader = "X-Payment-Signature"
+
+	maxWebhookBodySize = 1 << 20
+)
+
+type PaymentHandler struct {
+	PaymentService *service.PaymentService
+	Log            *logrus.Logger
+}
+
+func NewPaymentHandler(paymentService *service.PaymentService, log *logrus.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		PaymentService: paymentService,
+		Log:            log,
+	}
+}
+
+// @Summary Pay for an order
+// @Description Open a payment intent at the gateway for a pending order
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 201 {object} model.SuccessResponse[model.PaymentResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /orders/{id}/payments [post]
+func (h *PaymentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.CreatePaymentRequest{
+		OrderID: r.PathValue("id"),
+	}
+
+	response, err := h.PaymentService.Create(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to create payment: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		case errors.Is(err, e.ErrOrderNotPayable):
+			e.ErrorHandler(w, r, http.StatusConflict, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Payment webhook
+// @Description Receive a signed event from the payment gateway, redelivered events are acknowledged without side effects
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "HMAC-SHA256 signature of the body, as sha256=<hex>"
+// @Success 200 {object} model.SuccessResponse[model.PaymentWebhookResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	// The signature covers the raw bytes, so the body is read as is instead of decoded
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := h.PaymentService.HandleWebhook(r.Context(), payload, r.Header.Get(SignatureHeader))
+	if err != nil {
+		h.Log.Errorf("failed to handle payment webhook: %v", err)
+		switch {
+		case errors.Is(err, e.ErrInvalidSignature):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Simulate a sandbox payment
+// @Description Complete or fail a payment as the customer would, only registered with PAYMENT_SIMULATION=true and the sandbox gateway. The signed webhook it produces goes through the same handling as /payments/webhook
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Param request body model.SimulatePaymentRequest true "Outcome"
+// @Success 200 {object} model.SuccessResponse[model.PaymentWebhookResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /payments/{id}/simulate [post]
+func (h *PaymentHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SimulatePaymentRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.PaymentService.Simulate(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to simulate payment: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, err)
+		case errors.Is(err, e.ErrNotFound), errors.Is(err, e.ErrSimulationUnavailable):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
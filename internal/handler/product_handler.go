@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/savioruz/bake/internal/domain/model"
 	"github.com/savioruz/bake/internal/service"
@@ -35,6 +36,8 @@ func NewProductHandler(productService *service.ProductService, log *logrus.Logge
 // @Param limit query int false "Limit"
 // @Param sort query string false "Sort" Enums(id, name, description, price, stock, image, created_at, updated_at)
 // @Param order query string false "Order" Enums(ASC, DESC)
+// @Param category query string false "Category slug, subcategories included"
+// @Param tag query string false "Tag slug"
 // @Success 200 {object} model.SuccessResponse[[]model.ProductResponse]
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -74,10 +77,18 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Param price query string false "Price"
 // @Param stock query string false "Stock"
 // @Param image query string false "Image"
+// @Param q query string false "Full-text search on name and description, sorted by relevance unless sort is set"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param in_stock query bool false "Only products that are (true) or are not (false) in stock"
+// @Param created_from query string false "Created at or after, YYYY-MM-DD or RFC3339"
+// @Param created_to query string false "Created at or before, YYYY-MM-DD (whole day) or RFC3339"
 // @Param page query int false "Page"
 // @Param limit query int false "Limit"
-// @Param sort query string false "Sort" Enums(id, name, description, price, stock, image, created_at, updated_at)
+// @Param sort query string false "Sort" Enums(id, name, description, price, stock, image, created_at, updated_at, relevance)
 // @Param order query string false "Order" Enums(ASC, DESC)
+// @Param category query string false "Category slug, subcategories included"
+// @Param tag query string false "Tag slug"
 // @Success 200 {object} model.SuccessResponse[[]model.ProductResponse]
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
@@ -125,6 +136,65 @@ func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
 		query.Image = helper.StrToPtr(image)
 	}
 
+	if q := r.URL.Query().Get("q"); q != "" {
+		query.Q = helper.StrToPtr(q)
+		if r.URL.Query().Get("sort") == "" {
+			pagination.Sort = "relevance"
+		}
+	}
+
+	if minPrice := r.URL.Query().Get("min_price"); minPrice != "" {
+		if priceVal, err := strconv.ParseFloat(minPrice, 64); err == nil {
+			query.MinPrice = helper.FloatToPtr(priceVal)
+		} else {
+			e.ErrorHandler(w, r, http.StatusBadRequest, errors.New("invalid min_price format"))
+			return
+		}
+	}
+
+	if maxPrice := r.URL.Query().Get("max_price"); maxPrice != "" {
+		if priceVal, err := strconv.ParseFloat(maxPrice, 64); err == nil {
+			query.MaxPrice = helper.FloatToPtr(priceVal)
+		} else {
+			e.ErrorHandler(w, r, http.StatusBadRequest, errors.New("invalid max_price format"))
+			return
+		}
+	}
+
+	if inStock := r.URL.Query().Get("in_stock"); inStock != "" {
+		inStockVal, err := strconv.ParseBool(inStock)
+		if err != nil {
+			e.ErrorHandler(w, r, http.StatusBadRequest, errors.New("invalid in_stock format"))
+			return
+		}
+		query.InStock = &inStockVal
+	}
+
+	if createdFrom := r.URL.Query().Get("created_from"); createdFrom != "" {
+		from, _, err := parseDate(createdFrom)
+		if err != nil {
+			e.ErrorHandler(w, r, http.StatusBadRequest, errors.New("invalid created_from format"))
+			return
+		}
+		query.CreatedFrom = &from
+	}
+
+	if createdTo := r.URL.Query().Get("created_to"); createdTo != "" {
+		to, dateOnly, err := parseDate(createdTo)
+		if err != nil {
+			e.ErrorHandler(w, r, http.StatusBadRequest, errors.New("invalid created_to format"))
+			return
+		}
+		// A bare date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1).Add(-time.Second)
+		}
+		query.CreatedTo = &to
+	}
+
+	query.Category = helper.StrToPtr(r.URL.Query().Get("category"))
+	query.Tag = helper.StrToPtr(r.URL.Query().Get("tag"))
+
 	response, err := h.ProductService.Search(r.Context(), query, pagination)
 	if err != nil {
 		h.Log.Errorf("failed to search products: %v", err)
@@ -142,6 +212,52 @@ func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// @Summary Get products of a category
+// @Description Get the products of a category and of its subcategories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param sort query string false "Sort" Enums(id, name, description, price, stock, image, created_at, updated_at)
+// @Param order query string false "Order" Enums(ASC, DESC)
+// @Param tag query string false "Tag slug"
+// @Success 200 {object} model.SuccessResponse[[]model.ProductResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /categories/{slug}/products [get]
+func (h *ProductHandler) GetByCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetCategoryProductsRequest{
+		Slug: r.PathValue("slug"),
+	}
+	pagination := h.parsePagination(r)
+
+	response, err := h.ProductService.GetByCategory(r.Context(), request, pagination)
+	if err != nil {
+		h.Log.Errorf("failed to get products of category: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrCategoryNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 // @Summary Get product by ID
 // @Description Get product by ID
 // @Tags products
@@ -150,6 +266,7 @@ func (h *ProductHandler) Search(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "Product ID"
 // @Success 200 {object} model.SuccessResponse[model.ProductResponse]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -158,7 +275,7 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	request := helper.ParseParam(r)
+	request := r.PathValue("id")
 	h.Log.Info("Parsed request parameter: ", request)
 
 	response, err := h.ProductService.GetById(r.Context(), &model.GetProductRequest{ID: request})
@@ -207,7 +324,7 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.Log.Errorf("failed to create product: %v", err)
 		switch {
-		case errors.Is(err, e.ErrValidation):
+		case errors.Is(err, e.ErrValidation), errors.Is(err, e.ErrCategoryNotFound), errors.Is(err, e.ErrTagNotFound):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
@@ -239,7 +356,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	id := &model.DeleteProductRequest{
-		ID: helper.ParseParam(r),
+		ID: r.PathValue("id"),
 	}
 	request := &model.UpdateProductRequest{}
 	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
@@ -251,8 +368,10 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		h.Log.Errorf("failed to update product: %v", err)
 		switch {
-		case errors.Is(err, e.ErrValidation):
+		case errors.Is(err, e.ErrValidation), errors.Is(err, e.ErrCategoryNotFound), errors.Is(err, e.ErrTagNotFound):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
 		}
@@ -265,13 +384,14 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete a product
-// @Description Delete a product
+// @Description Hide a product from customers, its orders are kept and it can be restored. Its images stay stored until it is purged
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
 // @Success 200 {object} model.SuccessResponse[model.DeleteProductRequest]
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Security ApiKeyAuth
 // @Router /products/{id} [delete]
@@ -282,7 +402,7 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	id := &model.DeleteProductRequest{
-		ID: helper.ParseParam(r),
+		ID: r.PathValue("id"),
 	}
 
 	response, err := h.ProductService.Delete(r.Context(), id)
@@ -291,6 +411,137 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, e.ErrValidation):
 			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Get archived products
+// @Description Get the deleted and inactive products hidden from customers
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param sort query string false "Sort" Enums(id, name, description, price, stock, image, created_at, updated_at)
+// @Param order query string false "Order" Enums(ASC, DESC)
+// @Success 200 {object} model.SuccessResponse[[]model.ProductResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/archived [get]
+func (h *ProductHandler) GetArchived(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := h.parsePagination(r)
+	response, err := h.ProductService.GetArchived(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to get archived products: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Restore a product
+// @Description Undo the deletion of a product, an inactive product stays hidden until it is activated
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} model.SuccessResponse[model.ProductResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/restore [post]
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	id := &model.DeleteProductRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.ProductService.Restore(r.Context(), id)
+	if err != nil {
+		h.Log.Errorf("failed to restore product: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Purge a product
+// @Description Permanently remove a deleted product that was never ordered, with its images. Ordered products can never be purged, delete their images one by one to free the files
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} model.SuccessResponse[model.DeleteProductRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/purge [delete]
+func (h *ProductHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	id := &model.DeleteProductRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.ProductService.Purge(r.Context(), id)
+	if err != nil {
+		h.Log.Errorf("failed to purge product: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		case errors.Is(err, e.ErrNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, err)
+		case errors.Is(err, e.ErrProductNotDeleted), errors.Is(err, e.ErrProductHasOrders):
+			e.ErrorHandler(w, r, http.StatusConflict, err)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, err)
 		}
@@ -327,6 +578,19 @@ func (h *ProductHandler) parsePagination(r *http.Request) *model.ProductPaginati
 	if order := r.URL.Query().Get("order"); order != "" {
 		pagination.Order = strings.ToLower(order)
 	}
+	pagination.Category = r.URL.Query().Get("category")
+	pagination.Tag = r.URL.Query().Get("tag")
 
 	return pagination
 }
+
+// parseDate is a private helper function to parse a YYYY-MM-DD date or an RFC3339 timestamp,
+// reporting whether only a date was given
+func parseDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	return timestamp, false, err
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+// maxUploadFiles is the most files one upload request may carry
+const maxUploadFiles = 10
+
+type ProductImageHandler struct {
+	ProductImageService *service.ProductImageService
+	Log                 *logrus.Logger
+}
+
+func NewProductImageHandler(productImageService *service.ProductImageService, log *logrus.Logger) *ProductImageHandler {
+	return &ProductImageHandler{
+		ProductImageService: productImageService,
+		Log:                 log,
+	}
+}
+
+// @Summary Upload product images
+// @Description Upload one or more JPEG, PNG, GIF or WebP images, they are appended to the gallery of the product
+// @Tags products
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param images formData file true "Image files, repeat the field for several images"
+// @Success 201 {object} model.SuccessResponse[[]model.ProductImageResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 413 {object} model.ErrorResponse
+// @Failure 415 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/images [post]
+func (h *ProductImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	// Leave room for the multipart framing on top of the files themselves
+	maxSize := h.ProductImageService.MaxSize
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize*maxUploadFiles+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			e.ErrorHandler(w, r, http.StatusRequestEntityTooLarge, e.ErrImageTooLarge)
+			return
+		}
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["images"]
+	if len(files) > maxUploadFiles {
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	request := &model.UploadProductImagesRequest{
+		ProductID: r.PathValue("id"),
+		Images:    make([]model.UploadedImage, 0, len(files)),
+	}
+	for _, header := range files {
+		if header.Size > maxSize {
+			e.ErrorHandler(w, r, http.StatusRequestEntityTooLarge, e.ErrImageTooLarge)
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+		file.Close()
+		if err != nil {
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		request.Images = append(request.Images, model.UploadedImage{
+			Filename: header.Filename,
+			Data:     data,
+		})
+	}
+
+	response, err := h.ProductImageService.Upload(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to upload product images: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Reorder product images
+// @Description Set the order of the gallery, the first image is the cover. Every image of the product has to be listed once
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param order body model.ReorderProductImagesRequest true "Image order"
+// @Success 200 {object} model.SuccessResponse[[]model.ProductImageResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/images/order [put]
+func (h *ProductImageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.ReorderProductImagesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ProductID = r.PathValue("id")
+
+	response, err := h.ProductImageService.Reorder(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to reorder product images: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete a product image
+// @Description Delete an image and its thumbnail
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} model.SuccessResponse[model.GetProductImageRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/images/{image_id} [delete]
+func (h *ProductImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetProductImageRequest{
+		ProductID: r.PathValue("id"),
+		ID:        r.PathValue("image_id"),
+	}
+
+	response, err := h.ProductImageService.Delete(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete product image: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map product image errors to responses
+func (h *ProductImageHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation), errors.Is(err, e.ErrTooManyImages):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrNotFound), errors.Is(err, e.ErrImageNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrImageTooLarge):
+		e.ErrorHandler(w, r, http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, e.ErrUnsupportedImage):
+		e.ErrorHandler(w, r, http.StatusUnsupportedMediaType, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleHandler struct {
+	RoleService *service.RoleService
+	Log         *logrus.Logger
+}
+
+func NewRoleHandler(roleService *service.RoleService, log *logrus.Logger) *RoleHandler {
+	return &RoleHandler{
+		RoleService: roleService,
+		Log:         log,
+	}
+}
+
+// @Summary Get roles
+// @Description Get every role with its permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[[]model.RoleResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /roles [get]
+func (h *RoleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.RoleService.GetAll(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to get roles: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Get permissions
+// @Description Get every permission that can be granted to a role
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[[]model.PermissionResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /permissions [get]
+func (h *RoleHandler) GetPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.RoleService.GetPermissions(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to get permissions: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Save a role
+// @Description Create a role or replace its description and permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param role body model.SaveRoleRequest true "Role"
+// @Success 200 {object} model.SuccessResponse[model.RoleResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /roles/{name} [put]
+func (h *RoleHandler) Save(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SaveRoleRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.Name = r.PathValue("name")
+
+	response, err := h.RoleService.Save(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to save role: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete a role
+// @Description Delete a role that is not assigned to any user
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} model.SuccessResponse[model.GetRoleRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /roles/{name} [delete]
+func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetRoleRequest{
+		Name: r.PathValue("name"),
+	}
+
+	response, err := h.RoleService.Delete(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete role: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map role errors to responses
+func (h *RoleHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrRoleNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrRoleInUse), errors.Is(err, e.ErrRoleProtected):
+		e.ErrorHandler(w, r, http.StatusConflict, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -3,23 +3,31 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/savioruz/bake/internal/domain/model"
 	"github.com/savioruz/bake/internal/service"
 	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
 	"github.com/sirupsen/logrus"
 )
 
 type UserHandler struct {
-	UserService *service.UserService
-	Log         *logrus.Logger
+	UserService    *service.UserService
+	TrustedProxies []*net.IPNet
+	Log            *logrus.Logger
 }
 
-func NewUserHandler(userService *service.UserService, log *logrus.Logger) *UserHandler {
+func NewUserHandler(userService *service.UserService, trustedProxies []*net.IPNet, log *logrus.Logger) *UserHandler {
 	return &UserHandler{
-		UserService: userService,
-		Log:         log,
+		UserService:    userService,
+		TrustedProxies: trustedProxies,
+		Log:            log,
 	}
 }
 
@@ -29,6 +37,7 @@ func NewUserHandler(userService *service.UserService, log *logrus.Logger) *UserH
 // @Accept json
 // @Produce json
 // @Param user body model.UserRegisterRequest true "User"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe to replay"
 // @Success 201 {object} model.SuccessResponse[model.UserResponse]
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 409 {object} model.ErrorResponse
@@ -72,9 +81,11 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param user body model.UserLoginRequest true "User"
-// @Success 200 {object} model.SuccessResponse[model.TokenResponse]
+// @Success 200 {object} model.SuccessResponse[model.LoginResponse]
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
 // @Failure 500 {object} model.ErrorResponse
 // @Router /users/login [post]
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -89,17 +100,22 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
 		return
 	}
+	request.IP = helper.ClientIP(r, h.TrustedProxies)
 
 	response, err := h.UserService.Login(r.Context(), &request)
 	if err != nil {
 		h.Log.Errorf("failed to login: %v", err)
+		var locked *e.LockedError
 		switch {
+		case errors.As(err, &locked):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			e.ErrorHandler(w, r, http.StatusTooManyRequests, e.ErrAccountLocked)
 		case errors.Is(err, e.ErrValidation):
 			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
-		case errors.Is(err, e.ErrUserNotFound):
-			e.ErrorHandler(w, r, http.StatusNotFound, e.ErrUserNotFound)
 		case errors.Is(err, e.ErrCredential):
 			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrCredential)
+		case errors.Is(err, e.ErrAccountDisabled):
+			e.ErrorHandler(w, r, http.StatusForbidden, e.ErrAccountDisabled)
 		default:
 			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
 		}
@@ -138,7 +154,14 @@ func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	response, err := h.UserService.RefreshToken(r.Context(), &request)
 	if err != nil {
 		h.Log.Errorf("failed to refresh token: %v", err)
-		e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrCredential):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrCredential)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
 		return
 	}
 
@@ -179,3 +202,780 @@ func (h *UserHandler) Me(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
 }
+
+// @Summary Update current user
+// @Description Update the name or phone of the current user, absent fields are left alone
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body model.UpdateProfileRequest true "Profile"
+// @Success 200 {object} model.SuccessResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me [patch]
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	response, err := h.UserService.UpdateProfile(r.Context(), &request)
+	if err != nil {
+		h.Log.Errorf("failed to update profile: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrUnauthorized), errors.Is(err, e.ErrUserNotFound):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Change password
+// @Description Change the password of the current user, every session is signed out
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param password body model.ChangePasswordRequest true "Password"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/password [post]
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.ChangePassword(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to change password: %v", err)
+		h.handleAccountError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Delete current user
+// @Description Delete the account of the current user, personal data is anonymized and orders are kept
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param account body model.DeleteAccountRequest true "Account"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me [delete]
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.DeleteAccount(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to delete account: %v", err)
+		h.handleAccountError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAccountError is a private helper function to map password protected account errors to responses
+func (h *UserHandler) handleAccountError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation):
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+	case errors.Is(err, e.ErrInvalidPassword):
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrInvalidPassword)
+	case errors.Is(err, e.ErrUnauthorized), errors.Is(err, e.ErrUserNotFound):
+		e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+	}
+}
+
+// @Summary Log out
+// @Description Revoke the access token of the request, and the refresh token session when one is given
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param logout body model.LogoutRequest false "Logout"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/logout [post]
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	// The body is optional, an empty one only revokes the access token
+	var request model.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.Logout(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to logout: %v", err)
+		switch {
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+		case errors.Is(err, e.ErrCredential):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrCredential)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Log out all sessions
+// @Description Revoke every access and refresh token of the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/logout-all [post]
+func (h *UserHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	if err := h.UserService.LogoutAll(r.Context()); err != nil {
+		h.Log.Errorf("failed to logout all sessions: %v", err)
+		switch {
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Forgot password
+// @Description Email a password reset token, the response is the same whether the email is registered or not
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param forgot body model.ForgotPasswordRequest true "Forgot password"
+// @Success 202
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /users/password/forgot [post]
+func (h *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.ForgotPassword(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to request password reset: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Reset password
+// @Description Set a new password with a reset token and revoke every session of the user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param reset body model.ResetPasswordRequest true "Reset password"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /users/password/reset [post]
+func (h *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.ResetPassword(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to reset password: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrInvalidToken):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrInvalidToken)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Verify email
+// @Description Mark the email of a user as verified with the token sent to it
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /users/verify-email [get]
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.VerifyEmailRequest{
+		Token: r.URL.Query().Get("token"),
+	}
+
+	if err := h.UserService.VerifyEmail(r.Context(), request); err != nil {
+		h.Log.Errorf("failed to verify email: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrInvalidToken):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrInvalidToken)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Resend verification email
+// @Description Email a new verification token to the current user, nothing is sent once the email is verified
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 202
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/verify-email [post]
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	if err := h.UserService.ResendVerification(r.Context()); err != nil {
+		h.Log.Errorf("failed to resend verification email: %v", err)
+		switch {
+		case errors.Is(err, e.ErrUnauthorized):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token of a login and a TOTP or recovery code for the tokens
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param login body model.MFALoginRequest true "Two-factor login"
+// @Success 200 {object} model.SuccessResponse[model.TokenResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /users/login/2fa [post]
+func (h *UserHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.MFALoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+	request.IP = helper.ClientIP(r, h.TrustedProxies)
+
+	response, err := h.UserService.LoginMFA(r.Context(), &request)
+	if err != nil {
+		h.Log.Errorf("failed to complete two-factor login: %v", err)
+		var locked *e.LockedError
+		switch {
+		case errors.As(err, &locked):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			e.ErrorHandler(w, r, http.StatusTooManyRequests, e.ErrAccountLocked)
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrCredential):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrCredential)
+		case errors.Is(err, e.ErrInvalidMFACode):
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrInvalidMFACode)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Enroll in two-factor authentication
+// @Description Create a TOTP secret for the current user, it is enabled once a code is confirmed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse[model.TOTPEnrollResponse]
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/2fa/enroll [post]
+func (h *UserHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	response, err := h.UserService.EnrollTOTP(r.Context())
+	if err != nil {
+		h.Log.Errorf("failed to enroll two-factor authentication: %v", err)
+		h.handleMFAError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Confirm two-factor authentication
+// @Description Enable two-factor authentication with a code of the enrolled secret and get the recovery codes
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param code body model.TOTPCodeRequest true "Code"
+// @Success 200 {object} model.SuccessResponse[model.RecoveryCodesResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/2fa/confirm [post]
+func (h *UserHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.TOTPCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	response, err := h.UserService.ConfirmTOTP(r.Context(), &request)
+	if err != nil {
+		h.Log.Errorf("failed to confirm two-factor authentication: %v", err)
+		h.handleMFAError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off with a TOTP or recovery code
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param code body model.TOTPCodeRequest true "Code"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/me/2fa/disable [post]
+func (h *UserHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.TOTPCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+
+	if err := h.UserService.DisableTOTP(r.Context(), &request); err != nil {
+		h.Log.Errorf("failed to disable two-factor authentication: %v", err)
+		h.handleMFAError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMFAError is a private helper function to map two-factor errors to responses
+func (h *UserHandler) handleMFAError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation):
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+	case errors.Is(err, e.ErrUnauthorized):
+		e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+	case errors.Is(err, e.ErrInvalidMFACode):
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrInvalidMFACode)
+	case errors.Is(err, e.ErrMFAAlreadyEnabled), errors.Is(err, e.ErrMFANotEnabled):
+		e.ErrorHandler(w, r, http.StatusConflict, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+	}
+}
+
+// @Summary Assign a role
+// @Description Change the role of a user, their sessions are revoked so the new role applies right away
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param role body model.AssignRoleRequest true "Role"
+// @Success 200 {object} model.SuccessResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/{id}/role [put]
+func (h *UserHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+	request.UserID = r.PathValue("id")
+
+	response, err := h.UserService.AssignRole(r.Context(), &request)
+	if err != nil {
+		h.Log.Errorf("failed to assign role: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrRoleNotFound):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrRoleNotFound)
+		case errors.Is(err, e.ErrOwnAccount):
+			e.ErrorHandler(w, r, http.StatusForbidden, e.ErrOwnAccount)
+		case errors.Is(err, e.ErrUserNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, e.ErrUserNotFound)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Get all users
+// @Description Get all users, optionally searched by email or name and filtered by role
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param sort query string false "Sort" Enums(email, name, role, created_at, updated_at)
+// @Param order query string false "Order" Enums(ASC, DESC)
+// @Param q query string false "Search on email or name"
+// @Param role query string false "Role"
+// @Success 200 {object} model.SuccessResponse[[]model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users [get]
+func (h *UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := h.parsePagination(r)
+	response, err := h.UserService.GetAll(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to get all users: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Get user by ID
+// @Description Get user by ID
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.SuccessResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/{id} [get]
+func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetUserRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.UserService.GetByID(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to get user by id: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrUserNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, e.ErrUserNotFound)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Update a user
+// @Description Change the role of a user or disable them, their sessions are revoked right away
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body model.UpdateUserRequest true "User"
+// @Success 200 {object} model.SuccessResponse[model.UserResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/{id} [patch]
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	var request model.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.Log.Errorf("failed to decode request body: %v", err)
+		e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		return
+	}
+	request.ID = r.PathValue("id")
+
+	response, err := h.UserService.Update(r.Context(), &request)
+	if err != nil {
+		h.Log.Errorf("failed to update user: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrRoleNotFound):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrRoleNotFound)
+		case errors.Is(err, e.ErrOwnAccount):
+			e.ErrorHandler(w, r, http.StatusForbidden, e.ErrOwnAccount)
+		case errors.Is(err, e.ErrUserNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, e.ErrUserNotFound)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// @Summary Delete a user
+// @Description Delete a user without orders, users with orders can only be disabled
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.SuccessResponse[model.GetUserRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /users/{id} [delete]
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetUserRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.UserService.Delete(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete user: %v", err)
+		switch {
+		case errors.Is(err, e.ErrValidation):
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+		case errors.Is(err, e.ErrOwnAccount):
+			e.ErrorHandler(w, r, http.StatusForbidden, e.ErrOwnAccount)
+		case errors.Is(err, e.ErrUserNotFound):
+			e.ErrorHandler(w, r, http.StatusNotFound, e.ErrUserNotFound)
+		case errors.Is(err, e.ErrUserHasOrders):
+			e.ErrorHandler(w, r, http.StatusConflict, e.ErrUserHasOrders)
+		default:
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(model.NewSuccessResponse(response, nil))
+}
+
+// parsePagination is a private helper function to parse pagination and filter parameters
+func (h *UserHandler) parsePagination(r *http.Request) *model.UserPagination {
+	pagination := &model.UserPagination{
+		Page:  1,
+		Limit: 10,
+		Sort:  "created_at",
+		Order: "desc",
+	}
+
+	if page := r.URL.Query().Get("page"); page != "" {
+		if pageNum, err := strconv.Atoi(page); err == nil && pageNum > 0 {
+			pagination.Page = pageNum
+		}
+	}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if limitNum, err := strconv.Atoi(limit); err == nil && limitNum > 0 && limitNum <= 100 {
+			pagination.Limit = limitNum
+		}
+	}
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		pagination.Sort = sort
+	}
+	if order := r.URL.Query().Get("order"); order != "" {
+		pagination.Order = strings.ToLower(order)
+	}
+	pagination.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+	pagination.Role = r.URL.Query().Get("role")
+
+	return pagination
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/service"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type VariantHandler struct {
+	VariantService *service.VariantService
+	Log            *logrus.Logger
+}
+
+func NewVariantHandler(variantService *service.VariantService, log *logrus.Logger) *VariantHandler {
+	return &VariantHandler{
+		VariantService: variantService,
+		Log:            log,
+	}
+}
+
+// @Summary Get product options
+// @Description Get the variants and add-on groups a product can be ordered with
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} model.SuccessResponse[model.ProductOptionsResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Router /products/{id}/options [get]
+func (h *VariantHandler) GetOptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetProductRequest{
+		ID: r.PathValue("id"),
+	}
+
+	response, err := h.VariantService.GetOptions(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to get product options: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Create a variant
+// @Description Add a variant with its own SKU, price delta and stock to a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variant body model.SaveVariantRequest true "Variant"
+// @Success 201 {object} model.SuccessResponse[model.VariantResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SaveVariantRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ProductID = r.PathValue("id")
+
+	response, err := h.VariantService.SaveVariant(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to create variant: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Update a variant
+// @Description Update the SKU, name, price delta and stock of a variant
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variant_id path string true "Variant ID"
+// @Param variant body model.SaveVariantRequest true "Variant"
+// @Success 200 {object} model.SuccessResponse[model.VariantResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/variants/{variant_id} [put]
+func (h *VariantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SaveVariantRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ProductID = r.PathValue("id")
+	request.ID = r.PathValue("variant_id")
+
+	response, err := h.VariantService.SaveVariant(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update variant: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete a variant
+// @Description Delete a variant, orders keep the variant name they were placed with
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param variant_id path string true "Variant ID"
+// @Success 200 {object} model.SuccessResponse[model.GetVariantRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/variants/{variant_id} [delete]
+func (h *VariantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetVariantRequest{
+		ProductID: r.PathValue("id"),
+		ID:        r.PathValue("variant_id"),
+	}
+
+	response, err := h.VariantService.DeleteVariant(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete variant: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Create an option group
+// @Description Add a group of add-ons, such as candles or a custom message, to a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param group body model.SaveOptionGroupRequest true "Option group"
+// @Success 201 {object} model.SuccessResponse[model.OptionGroupResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/option-groups [post]
+func (h *VariantHandler) CreateOptionGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SaveOptionGroupRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ProductID = r.PathValue("id")
+
+	response, err := h.VariantService.SaveOptionGroup(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to create option group: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Update an option group
+// @Description Replace an option group, options without an id are added and options left out are removed
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param group_id path string true "Option group ID"
+// @Param group body model.SaveOptionGroupRequest true "Option group"
+// @Success 200 {object} model.SuccessResponse[model.OptionGroupResponse]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/option-groups/{group_id} [put]
+func (h *VariantHandler) UpdateOptionGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.SaveOptionGroupRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+		return
+	}
+	request.ProductID = r.PathValue("id")
+	request.ID = r.PathValue("group_id")
+
+	response, err := h.VariantService.SaveOptionGroup(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to update option group: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// @Summary Delete an option group
+// @Description Delete an option group with its options
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param group_id path string true "Option group ID"
+// @Success 200 {object} model.SuccessResponse[model.GetOptionGroupRequest]
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 500 {object} model.ErrorResponse
+// @Security ApiKeyAuth
+// @Router /products/{id}/option-groups/{group_id} [delete]
+func (h *VariantHandler) DeleteOptionGroup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
+
+	request := &model.GetOptionGroupRequest{
+		ProductID: r.PathValue("id"),
+		ID:        r.PathValue("group_id"),
+	}
+
+	response, err := h.VariantService.DeleteOptionGroup(r.Context(), request)
+	if err != nil {
+		h.Log.Errorf("failed to delete option group: %v", err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleError is a private helper function to map variant errors to responses
+func (h *VariantHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, e.ErrValidation), errors.Is(err, e.ErrInvalidOptions):
+		e.ErrorHandler(w, r, http.StatusBadRequest, err)
+	case errors.Is(err, e.ErrNotFound), errors.Is(err, e.ErrVariantNotFound), errors.Is(err, e.ErrOptionGroupNotFound):
+		e.ErrorHandler(w, r, http.StatusNotFound, err)
+	case errors.Is(err, e.ErrSKUExists):
+		e.ErrorHandler(w, r, http.StatusConflict, err)
+	default:
+		e.ErrorHandler(w, r, http.StatusInternalServerError, err)
+	}
+}
@@ -14,8 +14,8 @@ func NewAddressRepository(db *sqlx.DB) *AddressRepository {
 }
 
 func (r *AddressRepository) Create(tx *sqlx.Tx, address *entity.Address) error {
-	query := `INSERT INTO addresses (id, user_id, address_line, city, state, postal_code, country) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO addresses (id, user_id, address_line, city, state, postal_code, country, is_default, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
 		query,
@@ -26,12 +26,41 @@ func (r *AddressRepository) Create(tx *sqlx.Tx, address *entity.Address) error {
 		address.State,
 		address.PostalCode,
 		address.Country,
+		address.IsDefault,
+		address.CreatedAt,
+		address.UpdatedAt,
 	)
 	return err
 }
 
-func (r *AddressRepository) GetByUserID(tx *sqlx.Tx, userID string) (*entity.Address, error) {
-	query := `SELECT * FROM addresses WHERE user_id = ?`
+func (r *AddressRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Address, error) {
+	query := `SELECT * FROM addresses WHERE id = ?`
+
+	var address entity.Address
+	err := tx.Get(&address, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+// GetByIDAndUserID reads an address only when it belongs to the user
+func (r *AddressRepository) GetByIDAndUserID(tx *sqlx.Tx, id, userID string) (*entity.Address, error) {
+	query := `SELECT * FROM addresses WHERE id = ? AND user_id = ?`
+
+	var address entity.Address
+	err := tx.Get(&address, query, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+// GetDefaultByUserID reads the default address of a user, falling back to the oldest one
+func (r *AddressRepository) GetDefaultByUserID(tx *sqlx.Tx, userID string) (*entity.Address, error) {
+	query := `SELECT * FROM addresses WHERE user_id = ? ORDER BY is_default DESC, created_at ASC, id ASC LIMIT 1`
 
 	var address entity.Address
 	err := tx.Get(&address, query, userID)
@@ -41,3 +70,51 @@ func (r *AddressRepository) GetByUserID(tx *sqlx.Tx, userID string) (*entity.Add
 
 	return &address, nil
 }
+
+func (r *AddressRepository) GetAllByUserID(tx *sqlx.Tx, userID string) ([]entity.Address, error) {
+	query := `SELECT * FROM addresses WHERE user_id = ? ORDER BY is_default DESC, created_at ASC, id ASC`
+
+	var addresses []entity.Address
+	err := tx.Select(&addresses, query, userID)
+
+	return addresses, err
+}
+
+func (r *AddressRepository) Update(tx *sqlx.Tx, address *entity.Address) error {
+	query := `UPDATE addresses SET address_line = ?, city = ?, state = ?, postal_code = ?, country = ?, is_default = ?, updated_at = ?
+			  WHERE id = ? AND user_id = ?`
+
+	_, err := tx.Exec(
+		query,
+		address.AddressLine,
+		address.City,
+		address.State,
+		address.PostalCode,
+		address.Country,
+		address.IsDefault,
+		address.UpdatedAt,
+		address.ID,
+		address.UserID,
+	)
+	return err
+}
+
+// ClearDefault unsets the default flag on every address of the user
+func (r *AddressRepository) ClearDefault(tx *sqlx.Tx, userID string) error {
+	query := `UPDATE addresses SET is_default = FALSE WHERE user_id = ? AND is_default = TRUE`
+	_, err := tx.Exec(query, userID)
+	return err
+}
+
+// DeleteByUserID empties the address book of a user, orders keep their shipping snapshot
+func (r *AddressRepository) DeleteByUserID(tx *sqlx.Tx, userID string) error {
+	query := `DELETE FROM addresses WHERE user_id = ?`
+	_, err := tx.Exec(query, userID)
+	return err
+}
+
+func (r *AddressRepository) Delete(tx *sqlx.Tx, id, userID string) error {
+	query := `DELETE FROM addresses WHERE id = ? AND user_id = ?`
+	_, err := tx.Exec(query, id, userID)
+	return err
+}
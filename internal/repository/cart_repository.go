@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type CartRepository struct {
+	db *sqlx.DB
+}
+
+func NewCartRepository(db *sqlx.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// Create inserts a cart for the user, leaving an existing cart untouched
+func (r *CartRepository) Create(tx *sqlx.Tx, cart *entity.Cart) error {
+	query := `INSERT INTO carts (id, user_id, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?) 
+			  ON DUPLICATE KEY UPDATE id = id`
+
+	_, err := tx.Exec(
+		query,
+		cart.ID,
+		cart.UserID,
+		cart.CreatedAt,
+		cart.UpdatedAt,
+	)
+	return err
+}
+
+// GetByUserIDForUpdate reads the cart of a user and holds a row lock until the transaction ends
+func (r *CartRepository) GetByUserIDForUpdate(tx *sqlx.Tx, userID string) (*entity.Cart, error) {
+	query := `SELECT * FROM carts WHERE user_id = ? FOR UPDATE`
+
+	var cart entity.Cart
+	err := tx.Get(&cart, query, userID)
+
+	return &cart, err
+}
+
+func (r *CartRepository) GetItems(tx *sqlx.Tx, cartID string) ([]entity.CartItem, error) {
+	query := `SELECT * FROM cart_items WHERE cart_id = ? ORDER BY created_at, id`
+
+	var items []entity.CartItem
+	err := tx.Select(&items, query, cartID)
+
+	return items, err
+}
+
+// AddItem inserts a line or adds the quantity to the line already holding the product with the same
+// variant and add-ons, it reports whether a new line was inserted
+func (r *CartRepository) AddItem(tx *sqlx.Tx, item *entity.CartItem) (bool, error) {
+	query := `INSERT INTO cart_items (id, cart_id, product_id, variant_id, line_key, quantity, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?) 
+			  ON DUPLICATE KEY UPDATE quantity = quantity + VALUES(quantity), updated_at = VALUES(updated_at)`
+
+	result, err := tx.Exec(
+		query,
+		item.ID,
+		item.CartID,
+		item.ProductID,
+		item.VariantID,
+		item.LineKey,
+		item.Quantity,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// MySQL counts an insert as one affected row and an update of a duplicate as two
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *CartRepository) CreateItemOption(tx *sqlx.Tx, option *entity.CartItemOption) error {
+	query := `INSERT INTO cart_item_options (cart_item_id, option_id) VALUES (?, ?)`
+
+	_, err := tx.Exec(query, option.CartItemID, option.OptionID)
+	return err
+}
+
+// GetItemOptions loads the add-ons of every line of a cart in one query
+func (r *CartRepository) GetItemOptions(tx *sqlx.Tx, cartID string) ([]entity.CartItemOption, error) {
+	query := `SELECT o.* FROM cart_item_options o
+			  JOIN cart_items i ON i.id = o.cart_item_id
+			  WHERE i.cart_id = ?
+			  ORDER BY o.option_id`
+
+	var options []entity.CartItemOption
+	err := tx.Select(&options, query, cartID)
+
+	return options, err
+}
+
+func (r *CartRepository) UpdateItem(tx *sqlx.Tx, item *entity.CartItem) (int64, error) {
+	query := `UPDATE cart_items SET quantity = ?, updated_at = ? WHERE id = ? AND cart_id = ?`
+
+	result, err := tx.Exec(query, item.Quantity, item.UpdatedAt, item.ID, item.CartID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *CartRepository) DeleteItem(tx *sqlx.Tx, cartID, id string) (int64, error) {
+	query := `DELETE FROM cart_items WHERE id = ? AND cart_id = ?`
+
+	result, err := tx.Exec(query, id, cartID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *CartRepository) ClearItems(tx *sqlx.Tx, cartID string) error {
+	query := `DELETE FROM cart_items WHERE cart_id = ?`
+	_, err := tx.Exec(query, cartID)
+	return err
+}
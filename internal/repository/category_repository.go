@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type CategoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetAll returns every category, the tree is small enough to be assembled in memory
+func (r *CategoryRepository) GetAll(tx *sqlx.Tx) ([]entity.Category, error) {
+	query := `SELECT * FROM categories ORDER BY name ASC`
+
+	var categories []entity.Category
+	err := tx.Select(&categories, query)
+
+	return categories, err
+}
+
+func (r *CategoryRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Category, error) {
+	query := `SELECT * FROM categories WHERE id = ?`
+
+	var category entity.Category
+	err := tx.Get(&category, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepository) GetBySlug(tx *sqlx.Tx, slug string) (*entity.Category, error) {
+	query := `SELECT * FROM categories WHERE slug = ?`
+
+	var category entity.Category
+	err := tx.Get(&category, query, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepository) Create(tx *sqlx.Tx, category *entity.Category) error {
+	query := `INSERT INTO categories (id, parent_id, name, slug, description, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		category.ID,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+	return err
+}
+
+func (r *CategoryRepository) Update(tx *sqlx.Tx, category *entity.Category) error {
+	query := `UPDATE categories SET parent_id = ?, name = ?, slug = ?, description = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(
+		query,
+		category.ParentID,
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.UpdatedAt,
+		category.ID,
+	)
+	return err
+}
+
+func (r *CategoryRepository) HasChildren(tx *sqlx.Tx, id string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = ?)`
+
+	var exists bool
+	err := tx.Get(&exists, query, id)
+
+	return exists, err
+}
+
+func (r *CategoryRepository) Delete(tx *sqlx.Tx, id string) error {
+	query := `DELETE FROM categories WHERE id = ?`
+	_, err := tx.Exec(query, id)
+	return err
+}
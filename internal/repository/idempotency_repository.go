@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+// IdempotencyRepository works outside of service transactions because it wraps whole requests
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve claims a key for a new request, or returns the record that already holds it
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	now := time.Now()
+
+	// An expired key can be reused right away, the rest are left to DeleteExpired
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key_hash = ? AND expires_at < ?`, key.KeyHash, now); err != nil {
+		return nil, false, err
+	}
+
+	query := `INSERT IGNORE INTO idempotency_keys (key_hash, fingerprint, expires_at, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query, key.KeyHash, key.Fingerprint, key.ExpiresAt, now, now)
+	if err != nil {
+		return nil, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+	if affected == 1 {
+		return key, true, nil
+	}
+
+	var existing entity.IdempotencyKey
+	if err := r.db.GetContext(ctx, &existing, `SELECT * FROM idempotency_keys WHERE key_hash = ?`, key.KeyHash); err != nil {
+		return nil, false, err
+	}
+
+	return &existing, false, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	query := `UPDATE idempotency_keys SET status_code = ?, content_type = ?, response_body = ?, updated_at = ? WHERE key_hash = ?`
+
+	_, err := r.db.ExecContext(ctx, query, key.StatusCode, key.ContentType, key.ResponseBody, time.Now(), key.KeyHash)
+	return err
+}
+
+// Release frees a key so the request can be retried
+func (r *IdempotencyRepository) Release(ctx context.Context, keyHash string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key_hash = ?`, keyHash)
+	return err
+}
+
+// DeleteExpired purges every expired key so the table does not grow without bound
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < ?`, now)
+	return err
+}
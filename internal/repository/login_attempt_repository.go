@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+// LoginAttemptRepository works outside of service transactions so failures are kept when a login rolls back
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptRepository(db *sqlx.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) GetByKeys(ctx context.Context, keys []string) ([]entity.LoginAttempt, error) {
+	query, args, err := sqlx.In(`SELECT * FROM login_attempts WHERE attempt_key IN (?)`, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var attempts []entity.LoginAttempt
+	err = r.db.SelectContext(ctx, &attempts, r.db.Rebind(query), args...)
+
+	return attempts, err
+}
+
+// RecordFailure counts a failed attempt, failures older than the window start the count over
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*entity.LoginAttempt, error) {
+	now := time.Now()
+
+	// Stale keys are purged in small batches so the table does not grow without bound
+	query := `DELETE FROM login_attempts WHERE last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?) LIMIT 100`
+	if _, err := r.db.ExecContext(ctx, query, now.Add(-window), now); err != nil {
+		return nil, err
+	}
+
+	query = `INSERT INTO login_attempts (attempt_key, failures, last_failed_at) VALUES (?, 1, ?)
+			 ON DUPLICATE KEY UPDATE failures = IF(last_failed_at < ?, 1, failures + 1), last_failed_at = VALUES(last_failed_at)`
+	if _, err := r.db.ExecContext(ctx, query, key, now, now.Add(-window)); err != nil {
+		return nil, err
+	}
+
+	var attempt entity.LoginAttempt
+	if err := r.db.GetContext(ctx, &attempt, `SELECT * FROM login_attempts WHERE attempt_key = ?`, key); err != nil {
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?`, until, key)
+	return err
+}
+
+func (r *LoginAttemptRepository) DeleteByKeys(ctx context.Context, keys []string) error {
+	query, args, err := sqlx.In(`DELETE FROM login_attempts WHERE attempt_key IN (?)`, keys)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, r.db.Rebind(query), args...)
+	return err
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type OptionRepository struct {
+	db *sqlx.DB
+}
+
+func NewOptionRepository(db *sqlx.DB) *OptionRepository {
+	return &OptionRepository{db: db}
+}
+
+func (r *OptionRepository) GetGroupsByProductID(tx *sqlx.Tx, productID string) ([]entity.ProductOptionGroup, error) {
+	query := `SELECT * FROM product_option_groups WHERE product_id = ? ORDER BY created_at, id`
+
+	var groups []entity.ProductOptionGroup
+	err := tx.Select(&groups, query, productID)
+
+	return groups, err
+}
+
+func (r *OptionRepository) GetGroupByID(tx *sqlx.Tx, id string) (*entity.ProductOptionGroup, error) {
+	query := `SELECT * FROM product_option_groups WHERE id = ?`
+
+	var group entity.ProductOptionGroup
+	err := tx.Get(&group, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *OptionRepository) CreateGroup(tx *sqlx.Tx, group *entity.ProductOptionGroup) error {
+	query := `INSERT INTO product_option_groups (id, product_id, name, required, max_select, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, group.ID, group.ProductID, group.Name, group.Required, group.MaxSelect, group.CreatedAt, group.UpdatedAt)
+	return err
+}
+
+func (r *OptionRepository) UpdateGroup(tx *sqlx.Tx, group *entity.ProductOptionGroup) error {
+	query := `UPDATE product_option_groups SET name = ?, required = ?, max_select = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, group.Name, group.Required, group.MaxSelect, group.UpdatedAt, group.ID)
+	return err
+}
+
+func (r *OptionRepository) DeleteGroup(tx *sqlx.Tx, id string) error {
+	query := `DELETE FROM product_option_groups WHERE id = ?`
+	_, err := tx.Exec(query, id)
+	return err
+}
+
+// GetByGroupIDs loads the options of several groups at once
+func (r *OptionRepository) GetByGroupIDs(tx *sqlx.Tx, groupIDs []string) ([]entity.ProductOption, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM product_options WHERE group_id IN (?) ORDER BY price ASC, name ASC`, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []entity.ProductOption
+	err = tx.Select(&options, tx.Rebind(query), args...)
+
+	return options, err
+}
+
+// GetSelections loads the given options with the product and group they belong to
+func (r *OptionRepository) GetSelections(tx *sqlx.Tx, ids []string) ([]entity.ProductOptionSelection, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT o.*, g.product_id, g.name AS group_name FROM product_options o
+			  JOIN product_option_groups g ON g.id = o.group_id
+			  WHERE o.id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []entity.ProductOptionSelection
+	err = tx.Select(&options, tx.Rebind(query), args...)
+
+	return options, err
+}
+
+func (r *OptionRepository) Create(tx *sqlx.Tx, option *entity.ProductOption) error {
+	query := `INSERT INTO product_options (id, group_id, name, price, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, option.ID, option.GroupID, option.Name, option.Price, option.CreatedAt, option.UpdatedAt)
+	return err
+}
+
+func (r *OptionRepository) Update(tx *sqlx.Tx, option *entity.ProductOption) error {
+	query := `UPDATE product_options SET name = ?, price = ?, updated_at = ? WHERE id = ? AND group_id = ?`
+
+	_, err := tx.Exec(query, option.Name, option.Price, option.UpdatedAt, option.ID, option.GroupID)
+	return err
+}
+
+// DeleteExcept removes the options of a group that are not in keepIDs
+func (r *OptionRepository) DeleteExcept(tx *sqlx.Tx, groupID string, keepIDs []string) error {
+	if len(keepIDs) == 0 {
+		_, err := tx.Exec(`DELETE FROM product_options WHERE group_id = ?`, groupID)
+		return err
+	}
+
+	query, args, err := sqlx.In(`DELETE FROM product_options WHERE group_id = ? AND id NOT IN (?)`, groupID, keepIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(tx.Rebind(query), args...)
+	return err
+}
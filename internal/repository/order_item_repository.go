@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type OrderItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderItemRepository(db *sqlx.DB) *OrderItemRepository {
+	return &OrderItemRepository{db: db}
+}
+
+func (r *OrderItemRepository) Create(tx *sqlx.Tx, item *entity.OrderItem) error {
+	query := `INSERT INTO order_items (id, order_id, product_id, product_name, variant_id, variant_name, quantity, unit_price, subtotal, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		item.ID,
+		item.OrderID,
+		item.ProductID,
+		item.ProductName,
+		item.VariantID,
+		item.VariantName,
+		item.Quantity,
+		item.UnitPrice,
+		item.Subtotal,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+	return err
+}
+
+func (r *OrderItemRepository) GetByOrderID(tx *sqlx.Tx, orderID string) ([]entity.OrderItem, error) {
+	query := `SELECT * FROM order_items WHERE order_id = ? ORDER BY created_at, id`
+
+	var items []entity.OrderItem
+	err := tx.Select(&items, query, orderID)
+
+	return items, err
+}
+
+// GetByOrderIDs loads the items of several orders in one query
+func (r *OrderItemRepository) GetByOrderIDs(tx *sqlx.Tx, orderIDs []string) ([]entity.OrderItem, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM order_items WHERE order_id IN (?) ORDER BY created_at, id`, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []entity.OrderItem
+	err = tx.Select(&items, tx.Rebind(query), args...)
+
+	return items, err
+}
+
+func (r *OrderItemRepository) CreateOption(tx *sqlx.Tx, option *entity.OrderItemOption) error {
+	query := `INSERT INTO order_item_options (id, order_item_id, option_id, group_name, option_name, price) 
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, option.ID, option.OrderItemID, option.OptionID, option.GroupName, option.OptionName, option.Price)
+	return err
+}
+
+// GetOptionsByItemIDs loads the add-ons of several order lines in one query
+func (r *OrderItemRepository) GetOptionsByItemIDs(tx *sqlx.Tx, itemIDs []string) ([]entity.OrderItemOption, error) {
+	if len(itemIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM order_item_options WHERE order_item_id IN (?) ORDER BY group_name, option_name`, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []entity.OrderItemOption
+	err = tx.Select(&options, tx.Rebind(query), args...)
+
+	return options, err
+}
@@ -15,16 +15,19 @@ func NewOrderRepository(db *sqlx.DB) *OrderRepository {
 }
 
 func (r *OrderRepository) Create(tx *sqlx.Tx, order *entity.Order) error {
-	query := `INSERT INTO orders (id, user_id, product_id, address_id, quantity, total_price, status, created_at, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO orders (id, user_id, address_id, shipping_address_line, shipping_city, shipping_state, shipping_postal_code, shipping_country, total_price, status, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
 		query,
 		order.ID,
 		order.UserID,
-		order.ProductID,
 		order.AddressID,
-		order.Quantity,
+		order.ShippingAddressLine,
+		order.ShippingCity,
+		order.ShippingState,
+		order.ShippingPostalCode,
+		order.ShippingCountry,
 		order.TotalPrice,
 		order.Status,
 		order.CreatedAt,
@@ -42,22 +45,49 @@ func (r *OrderRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Order, error)
 	return &order, err
 }
 
-func (r *OrderRepository) GetAll(tx *sqlx.Tx, pagination *model.OrderPagination) ([]entity.Order, int, error) {
+// GetByIDForUpdate reads an order and holds a row lock until the transaction ends
+func (r *OrderRepository) GetByIDForUpdate(tx *sqlx.Tx, id string) (*entity.Order, error) {
+	query := `SELECT * FROM orders WHERE id = ? FOR UPDATE`
+
+	var order entity.Order
+	err := tx.Get(&order, query, id)
+
+	return &order, err
+}
+
+func (r *OrderRepository) UpdateStatus(tx *sqlx.Tx, order *entity.Order) error {
+	query := `UPDATE orders SET status = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, order.Status, order.UpdatedAt, order.ID)
+	return err
+}
+
+// GetAll lists orders, limited to the given user unless userID is empty
+func (r *OrderRepository) GetAll(tx *sqlx.Tx, userID string, pagination *model.OrderPagination) ([]entity.Order, int, error) {
 	baseQuery := `SELECT * FROM orders`
 	countQuery := `SELECT COUNT(*) FROM orders`
 
+	args := []interface{}{}
+	if userID != "" {
+		baseQuery += ` WHERE user_id = ?`
+		countQuery += ` WHERE user_id = ?`
+		args = append(args, userID)
+	}
+
 	baseQuery += ` ORDER BY ` + pagination.Sort + ` ` + pagination.Order
 
 	offset := (pagination.Page - 1) * pagination.Limit
 	baseQuery += ` LIMIT ? OFFSET ?`
 
+	paginationArgs := append(args, pagination.Limit, offset)
+
 	var total int
-	if err := tx.Get(&total, countQuery); err != nil {
+	if err := tx.Get(&total, countQuery, args...); err != nil {
 		return nil, 0, err
 	}
 
 	var orders []entity.Order
-	err := tx.Select(&orders, baseQuery, pagination.Limit, offset)
+	err := tx.Select(&orders, baseQuery, paginationArgs...)
 
 	return orders, total, err
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type PaymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) Create(tx *sqlx.Tx, payment *entity.Payment) error {
+	query := `INSERT INTO payments (id, order_id, gateway, intent_id, amount, currency, status, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		payment.ID,
+		payment.OrderID,
+		payment.Gateway,
+		payment.IntentID,
+		payment.Amount,
+		payment.Currency,
+		payment.Status,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PaymentRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Payment, error) {
+	query := `SELECT * FROM payments WHERE id = ?`
+
+	var payment entity.Payment
+	err := tx.Get(&payment, query, id)
+
+	return &payment, err
+}
+
+// GetByOrderIDForUpdate reads every payment of an order and holds row locks until the transaction ends
+func (r *PaymentRepository) GetByOrderIDForUpdate(tx *sqlx.Tx, orderID string) ([]entity.Payment, error) {
+	query := `SELECT * FROM payments WHERE order_id = ? ORDER BY created_at FOR UPDATE`
+
+	var payments []entity.Payment
+	err := tx.Select(&payments, query, orderID)
+
+	return payments, err
+}
+
+// GetByIntentIDForUpdate reads a payment by its gateway intent and holds a row lock until the transaction ends
+func (r *PaymentRepository) GetByIntentIDForUpdate(tx *sqlx.Tx, gateway, intentID string) (*entity.Payment, error) {
+	query := `SELECT * FROM payments WHERE gateway = ? AND intent_id = ? FOR UPDATE`
+
+	var payment entity.Payment
+	err := tx.Get(&payment, query, gateway, intentID)
+
+	return &payment, err
+}
+
+func (r *PaymentRepository) UpdateStatus(tx *sqlx.Tx, payment *entity.Payment) error {
+	query := `UPDATE payments SET status = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, payment.Status, payment.UpdatedAt, payment.ID)
+	return err
+}
+
+// CreateEvent records a webhook event and reports zero rows affected when it was already recorded
+func (r *PaymentRepository) CreateEvent(tx *sqlx.Tx, event *entity.PaymentEvent) (int64, error) {
+	query := `INSERT IGNORE INTO payment_events (id, gateway, type, intent_id, payload, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	result, err := tx.Exec(
+		query,
+		event.ID,
+		event.Gateway,
+		event.Type,
+		event.IntentID,
+		event.Payload,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type ProductImageRepository struct {
+	db *sqlx.DB
+}
+
+func NewProductImageRepository(db *sqlx.DB) *ProductImageRepository {
+	return &ProductImageRepository{db: db}
+}
+
+func (r *ProductImageRepository) GetByProductID(tx *sqlx.Tx, productID string) ([]entity.ProductImage, error) {
+	query := `SELECT * FROM product_images WHERE product_id = ? ORDER BY position, created_at, id`
+
+	var images []entity.ProductImage
+	err := tx.Select(&images, query, productID)
+
+	return images, err
+}
+
+// GetByProductIDs loads the images of several products at once
+func (r *ProductImageRepository) GetByProductIDs(tx *sqlx.Tx, productIDs []string) ([]entity.ProductImage, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM product_images WHERE product_id IN (?) ORDER BY position, created_at, id`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []entity.ProductImage
+	err = tx.Select(&images, tx.Rebind(query), args...)
+
+	return images, err
+}
+
+func (r *ProductImageRepository) GetByID(tx *sqlx.Tx, id string) (*entity.ProductImage, error) {
+	query := `SELECT * FROM product_images WHERE id = ?`
+
+	var image entity.ProductImage
+	err := tx.Get(&image, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// NextPosition returns the position after the last image of a product
+func (r *ProductImageRepository) NextPosition(tx *sqlx.Tx, productID string) (int, error) {
+	query := `SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = ?`
+
+	var position int
+	err := tx.Get(&position, query, productID)
+
+	return position, err
+}
+
+func (r *ProductImageRepository) Create(tx *sqlx.Tx, image *entity.ProductImage) error {
+	query := `INSERT INTO product_images (id, product_id, object_key, thumbnail_key, content_type, size, width, height, position, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		image.ID,
+		image.ProductID,
+		image.ObjectKey,
+		image.ThumbnailKey,
+		image.ContentType,
+		image.Size,
+		image.Width,
+		image.Height,
+		image.Position,
+		image.CreatedAt,
+		image.UpdatedAt,
+	)
+	return err
+}
+
+func (r *ProductImageRepository) UpdatePosition(tx *sqlx.Tx, id string, position int) error {
+	query := `UPDATE product_images SET position = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, position, id)
+	return err
+}
+
+func (r *ProductImageRepository) Delete(tx *sqlx.Tx, id string) error {
+	query := `DELETE FROM product_images WHERE id = ?`
+	_, err := tx.Exec(query, id)
+	return err
+}
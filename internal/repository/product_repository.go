@@ -1,11 +1,17 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/savioruz/bake/internal/domain/entity"
 	"github.com/savioruz/bake/internal/domain/model"
 )
 
+// visibleProduct keeps deleted and inactive products out of what customers can browse and buy
+const visibleProduct = `deleted_at IS NULL AND is_active = TRUE`
+
 type ProductRepository struct {
 	db *sqlx.DB
 }
@@ -14,72 +20,55 @@ func NewProductRepository(db *sqlx.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (r *ProductRepository) GetAll(tx *sqlx.Tx, pagination *model.ProductPagination) ([]entity.Product, int, error) {
-	baseQuery := `SELECT * FROM products`
-	countQuery := `SELECT COUNT(*) FROM products`
-
-	baseQuery += ` ORDER BY ` + pagination.Sort + ` ` + pagination.Order
-
-	offset := (pagination.Page - 1) * pagination.Limit
-	baseQuery += ` LIMIT ? OFFSET ?`
-
-	var total int
-	if err := tx.Get(&total, countQuery); err != nil {
-		return nil, 0, err
-	}
-
-	var products []entity.Product
-	err := tx.Select(&products, baseQuery, pagination.Limit, offset)
+// GetAll lists visible products, narrowed down to categoryIDs when they are not nil and to the tag slug of the pagination
+func (r *ProductRepository) GetAll(tx *sqlx.Tx, pagination *model.ProductPagination, categoryIDs []string) ([]entity.Product, int, error) {
+	builder := newQueryBuilder(`products`).where(visibleProduct)
+	filterCategoryTag(builder, categoryIDs, pagination.Tag)
+	sortProducts(builder, pagination, ``)
 
-	return products, total, err
+	return r.list(tx, builder, pagination)
 }
 
-func (r *ProductRepository) Search(tx *sqlx.Tx, query *model.ProductQuery, pagination *model.ProductPagination) ([]entity.Product, int, error) {
-	baseQuery := `SELECT * FROM products WHERE 1=1`
-	countQuery := `SELECT COUNT(*) FROM products WHERE 1=1`
+// Search lists visible products matching the query, narrowed down to categoryIDs when they are not nil.
+// Q is looked up in the fulltext index first and falls back to LIKE when that finds nothing,
+// e.g. for words shorter than the minimum token size or stopwords
+func (r *ProductRepository) Search(tx *sqlx.Tx, query *model.ProductQuery, pagination *model.ProductPagination, categoryIDs []string) ([]entity.Product, int, error) {
+	builder := searchFilter(query, categoryIDs)
+	if query.Q == nil || *query.Q == "" {
+		sortProducts(builder, pagination, ``)
+		return r.list(tx, builder, pagination)
+	}
 
-	args := []interface{}{}
+	const match = `MATCH(name, description) AGAINST (? IN NATURAL LANGUAGE MODE)`
+	builder.where(match, *query.Q)
+	sortProducts(builder, pagination, match, *query.Q)
 
-	if query.ID != nil {
-		baseQuery += ` AND id = ?`
-		countQuery += ` AND id = ?`
-		args = append(args, *query.ID)
-	}
-	if query.Name != nil {
-		baseQuery += ` AND name LIKE ?`
-		countQuery += ` AND name LIKE ?`
-		args = append(args, "%"+*query.Name+"%")
-	}
-	if query.Description != nil {
-		baseQuery += ` AND description LIKE ?`
-		countQuery += ` AND description LIKE ?`
-		args = append(args, "%"+*query.Description+"%")
-	}
-	if query.Price != nil {
-		baseQuery += ` AND price = ?`
-		countQuery += ` AND price = ?`
-		args = append(args, *query.Price)
-	}
-	if query.Stock != nil {
-		baseQuery += ` AND stock = ?`
-		countQuery += ` AND stock = ?`
-		args = append(args, *query.Stock)
+	products, total, err := r.list(tx, builder, pagination)
+	if err != nil || total > 0 {
+		return products, total, err
 	}
 
-	baseQuery += ` ORDER BY ` + pagination.Sort + ` ` + pagination.Order
+	pattern := likePattern(*query.Q)
+	builder = searchFilter(query, categoryIDs)
+	builder.where(`(name LIKE ? OR description LIKE ?)`, pattern, pattern)
+	sortProducts(builder, pagination, `CASE WHEN name LIKE ? THEN 1 ELSE 0 END`, pattern)
 
-	offset := (pagination.Page - 1) * pagination.Limit
-	baseQuery += ` LIMIT ? OFFSET ?`
+	return r.list(tx, builder, pagination)
+}
 
-	paginationArgs := append(args, pagination.Limit, offset)
+// list runs the builder for one page and counts every matching product
+func (r *ProductRepository) list(tx *sqlx.Tx, builder *queryBuilder, pagination *model.ProductPagination) ([]entity.Product, int, error) {
+	countQuery, countArgs := builder.buildCount()
 
 	var total int
-	if err := tx.Get(&total, countQuery, args...); err != nil {
+	if err := tx.Get(&total, countQuery, countArgs...); err != nil {
 		return nil, 0, err
 	}
 
+	query, args := builder.paginate(pagination.Page, pagination.Limit).build()
+
 	var products []entity.Product
-	err := tx.Select(&products, baseQuery, paginationArgs...)
+	err := tx.Select(&products, query, args...)
 
 	return products, total, err
 }
@@ -93,18 +82,68 @@ func (r *ProductRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Product, er
 	return &product, err
 }
 
+// GetActiveByID reads a product only while customers can see it
+func (r *ProductRepository) GetActiveByID(tx *sqlx.Tx, id string) (*entity.Product, error) {
+	query := `SELECT * FROM products WHERE id = ? AND ` + visibleProduct
+
+	var product entity.Product
+	err := tx.Get(&product, query, id)
+
+	return &product, err
+}
+
+// GetArchived lists the products hidden from customers, deleted or inactive, for the admin
+func (r *ProductRepository) GetArchived(tx *sqlx.Tx, pagination *model.ProductPagination) ([]entity.Product, int, error) {
+	builder := newQueryBuilder(`products`).where(`(deleted_at IS NOT NULL OR is_active = FALSE)`)
+	sortProducts(builder, pagination, ``)
+
+	return r.list(tx, builder, pagination)
+}
+
+// GetByIDForUpdate reads a product and holds a row lock until the transaction ends
+func (r *ProductRepository) GetByIDForUpdate(tx *sqlx.Tx, id string) (*entity.Product, error) {
+	query := `SELECT * FROM products WHERE id = ? FOR UPDATE`
+
+	var product entity.Product
+	err := tx.Get(&product, query, id)
+
+	return &product, err
+}
+
+// DecrementStock subtracts quantity only when enough stock is left and returns the affected rows
+func (r *ProductRepository) DecrementStock(tx *sqlx.Tx, id string, quantity int) (int64, error) {
+	query := `UPDATE products SET stock = stock - ?, updated_at = ? WHERE id = ? AND stock >= ?`
+
+	result, err := tx.Exec(query, quantity, time.Now(), id, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// IncrementStock returns quantity to the product, e.g. when an order is cancelled
+func (r *ProductRepository) IncrementStock(tx *sqlx.Tx, id string, quantity int) error {
+	query := `UPDATE products SET stock = stock + ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, quantity, time.Now(), id)
+	return err
+}
+
 func (r *ProductRepository) Create(tx *sqlx.Tx, product *entity.Product) error {
-	query := `INSERT INTO products (id, name, description, price, stock, image, created_at, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO products (id, category_id, name, description, price, stock, image, is_active, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := tx.Exec(
 		query,
 		product.ID,
+		product.CategoryID,
 		product.Name,
 		product.Description,
 		product.Price,
 		product.Stock,
 		product.Image,
+		product.IsActive,
 		product.CreatedAt,
 		product.UpdatedAt,
 	)
@@ -112,23 +151,143 @@ func (r *ProductRepository) Create(tx *sqlx.Tx, product *entity.Product) error {
 }
 
 func (r *ProductRepository) Update(tx *sqlx.Tx, product *entity.Product) error {
-	query := `UPDATE products SET name = ?, description = ?, price = ?, stock = ?, image = ?, updated_at = ? WHERE id = ?`
+	query := `UPDATE products SET category_id = ?, name = ?, description = ?, price = ?, stock = ?, image = ?, is_active = ?, updated_at = ? WHERE id = ?`
 
 	_, err := tx.Exec(
 		query,
+		product.CategoryID,
 		product.Name,
 		product.Description,
 		product.Price,
 		product.Stock,
 		product.Image,
+		product.IsActive,
 		product.UpdatedAt,
 		product.ID,
 	)
 	return err
 }
 
+// SoftDelete hides the product while keeping its row for the orders referencing it
+// and returns the affected rows, zero when it was already deleted
+func (r *ProductRepository) SoftDelete(tx *sqlx.Tx, id string) (int64, error) {
+	query := `UPDATE products SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`
+
+	now := time.Now()
+	result, err := tx.Exec(query, now, now, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Restore undoes SoftDelete
+func (r *ProductRepository) Restore(tx *sqlx.Tx, id string) error {
+	query := `UPDATE products SET deleted_at = NULL, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, time.Now(), id)
+	return err
+}
+
+// HasOrders reports whether any order item references the product, those rows block a hard delete
+func (r *ProductRepository) HasOrders(tx *sqlx.Tx, id string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM order_items WHERE product_id = ?)`
+
+	var exists bool
+	err := tx.Get(&exists, query, id)
+
+	return exists, err
+}
+
+// Delete removes the product for good, cascading to its cart items, tags, variants and images
 func (r *ProductRepository) Delete(tx *sqlx.Tx, id string) error {
 	query := `DELETE FROM products WHERE id = ?`
 	_, err := tx.Exec(query, id)
 	return err
 }
+
+// filterCategoryTag adds the conditions shared by product lists. A nil categoryIDs does not filter,
+// an empty one matches nothing
+func filterCategoryTag(builder *queryBuilder, categoryIDs []string, tag string) {
+	if categoryIDs != nil {
+		builder.whereIn(`category_id`, categoryIDs)
+	}
+	if tag != "" {
+		builder.where(`id IN (SELECT pt.product_id FROM product_tags pt JOIN tags t ON t.id = pt.tag_id WHERE t.slug = ?)`, tag)
+	}
+}
+
+// searchFilter applies every field of the query except Q
+func searchFilter(query *model.ProductQuery, categoryIDs []string) *queryBuilder {
+	builder := newQueryBuilder(`products`).where(visibleProduct)
+
+	if query.ID != nil {
+		builder.where(`id = ?`, *query.ID)
+	}
+	if query.Name != nil {
+		builder.where(`name LIKE ?`, likePattern(*query.Name))
+	}
+	if query.Description != nil {
+		builder.where(`description LIKE ?`, likePattern(*query.Description))
+	}
+	if query.Image != nil {
+		builder.where(`image LIKE ?`, likePattern(*query.Image))
+	}
+	if query.Price != nil {
+		builder.where(`price = ?`, *query.Price)
+	}
+	if query.MinPrice != nil {
+		builder.where(`price >= ?`, *query.MinPrice)
+	}
+	if query.MaxPrice != nil {
+		builder.where(`price <= ?`, *query.MaxPrice)
+	}
+	if query.Stock != nil {
+		builder.where(`stock = ?`, *query.Stock)
+	}
+	if query.InStock != nil {
+		// Products sold by variant are in stock while any of their variants is
+		inStock := `(EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id AND v.stock > 0)
+			OR (stock > 0 AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id)))`
+		if *query.InStock {
+			builder.where(inStock)
+		} else {
+			builder.where(`NOT ` + inStock)
+		}
+	}
+	if query.CreatedFrom != nil {
+		builder.where(`created_at >= ?`, *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		builder.where(`created_at <= ?`, *query.CreatedTo)
+	}
+
+	tag := ""
+	if query.Tag != nil {
+		tag = *query.Tag
+	}
+	filterCategoryTag(builder, categoryIDs, tag)
+
+	return builder
+}
+
+// sortProducts orders by the whitelisted pagination column, relevance sorts by the given
+// expression and falls back to the newest products when there is nothing to rank by
+func sortProducts(builder *queryBuilder, pagination *model.ProductPagination, relevance string, args ...interface{}) {
+	switch {
+	case pagination.Sort != "relevance":
+		builder.orderBy(pagination.Sort + ` ` + pagination.Order)
+	case relevance != "":
+		builder.orderBy(relevance+` `+pagination.Order, args...)
+		builder.orderBy(`created_at DESC`)
+	default:
+		builder.orderBy(`created_at ` + pagination.Order)
+	}
+}
+
+// likePattern matches value anywhere in a column, treating % and _ in it literally
+func likePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
@@ -0,0 +1,83 @@
+package repository
+
+import "strings"
+
+// queryBuilder composes a SELECT from independent conditions, keeping every value
+// in a placeholder so filters never splice user input into the SQL text
+type queryBuilder struct {
+	table      string
+	conditions []string
+	args       []interface{}
+	orders     []string
+	orderArgs  []interface{}
+	limit      int
+	offset     int
+}
+
+func newQueryBuilder(table string) *queryBuilder {
+	return &queryBuilder{table: table}
+}
+
+// where adds a condition that every row has to match
+func (b *queryBuilder) where(condition string, args ...interface{}) *queryBuilder {
+	b.conditions = append(b.conditions, condition)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// whereIn matches rows whose column is one of values, an empty values matches nothing
+func (b *queryBuilder) whereIn(column string, values []string) *queryBuilder {
+	if len(values) == 0 {
+		return b.where(`1=0`)
+	}
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+
+	return b.where(column+` IN (?`+strings.Repeat(`, ?`, len(values)-1)+`)`, args...)
+}
+
+// orderBy appends a sort expression, the caller has to whitelist column names
+func (b *queryBuilder) orderBy(expression string, args ...interface{}) *queryBuilder {
+	b.orders = append(b.orders, expression)
+	b.orderArgs = append(b.orderArgs, args...)
+	return b
+}
+
+func (b *queryBuilder) paginate(page, limit int) *queryBuilder {
+	b.limit = limit
+	b.offset = (page - 1) * limit
+	return b
+}
+
+// build returns the select query with its arguments in placeholder order
+func (b *queryBuilder) build() (string, []interface{}) {
+	query := `SELECT * FROM ` + b.table + b.whereClause()
+	args := append([]interface{}{}, b.args...)
+
+	if len(b.orders) > 0 {
+		query += ` ORDER BY ` + strings.Join(b.orders, `, `)
+		args = append(args, b.orderArgs...)
+	}
+	if b.limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, b.limit, b.offset)
+	}
+
+	return query, args
+}
+
+// buildCount returns a query counting every matching row, ignoring order and pagination
+func (b *queryBuilder) buildCount() (string, []interface{}) {
+	return `SELECT COUNT(*) FROM ` + b.table + b.whereClause(), b.args
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.conditions) == 0 {
+		return ``
+	}
+
+	return ` WHERE ` + strings.Join(b.conditions, ` AND `)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type RecoveryCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewRecoveryCodeRepository(db *sqlx.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+func (r *RecoveryCodeRepository) Create(tx *sqlx.Tx, code *entity.RecoveryCode) error {
+	query := `INSERT INTO recovery_codes (id, user_id, code_hash, created_at) VALUES (?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, code.ID, code.UserID, code.CodeHash, code.CreatedAt)
+	return err
+}
+
+// GetUnusedForUpdate reads an unused code of a user and holds a row lock so it can only be used once
+func (r *RecoveryCodeRepository) GetUnusedForUpdate(tx *sqlx.Tx, userID, codeHash string) (*entity.RecoveryCode, error) {
+	query := `SELECT * FROM recovery_codes WHERE user_id = ? AND code_hash = ? AND used_at IS NULL FOR UPDATE`
+
+	var code entity.RecoveryCode
+	err := tx.Get(&code, query, userID, codeHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+func (r *RecoveryCodeRepository) MarkUsed(tx *sqlx.Tx, id string, usedAt time.Time) error {
+	query := `UPDATE recovery_codes SET used_at = ? WHERE id = ?`
+	_, err := tx.Exec(query, usedAt, id)
+	return err
+}
+
+func (r *RecoveryCodeRepository) DeleteByUserID(tx *sqlx.Tx, userID string) error {
+	query := `DELETE FROM recovery_codes WHERE user_id = ?`
+	_, err := tx.Exec(query, userID)
+	return err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type RefreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (r *RefreshTokenRepository) Create(tx *sqlx.Tx, token *entity.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, token.ID, token.UserID, token.FamilyID, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// GetByIDForUpdate reads a refresh token and holds a row lock so it can only be rotated once
+func (r *RefreshTokenRepository) GetByIDForUpdate(tx *sqlx.Tx, id string) (*entity.RefreshToken, error) {
+	query := `SELECT * FROM refresh_tokens WHERE id = ? FOR UPDATE`
+
+	var token entity.RefreshToken
+	err := tx.Get(&token, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) MarkUsed(tx *sqlx.Tx, id string, usedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET used_at = ? WHERE id = ?`
+	_, err := tx.Exec(query, usedAt, id)
+	return err
+}
+
+// RevokeFamily revokes every token issued from the same login
+func (r *RefreshTokenRepository) RevokeFamily(tx *sqlx.Tx, familyID string, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	_, err := tx.Exec(query, revokedAt, familyID)
+	return err
+}
+
+// RevokeByUserID revokes every refresh token of a user
+func (r *RefreshTokenRepository) RevokeByUserID(tx *sqlx.Tx, userID string, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := tx.Exec(query, revokedAt, userID)
+	return err
+}
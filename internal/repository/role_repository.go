@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type RoleRepository struct {
+	db *sqlx.DB
+}
+
+func NewRoleRepository(db *sqlx.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+func (r *RoleRepository) GetAll(tx *sqlx.Tx) ([]entity.Role, error) {
+	query := `SELECT * FROM roles ORDER BY name ASC`
+
+	var roles []entity.Role
+	err := tx.Select(&roles, query)
+
+	return roles, err
+}
+
+func (r *RoleRepository) GetByName(tx *sqlx.Tx, name string) (*entity.Role, error) {
+	query := `SELECT * FROM roles WHERE name = ?`
+
+	var role entity.Role
+	err := tx.Get(&role, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// Save creates a role or updates the description of an existing one
+func (r *RoleRepository) Save(tx *sqlx.Tx, role *entity.Role) error {
+	query := `INSERT INTO roles (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE description = VALUES(description), updated_at = VALUES(updated_at)`
+
+	_, err := tx.Exec(query, role.Name, role.Description, role.CreatedAt, role.UpdatedAt)
+	return err
+}
+
+func (r *RoleRepository) Delete(tx *sqlx.Tx, name string) error {
+	query := `DELETE FROM roles WHERE name = ?`
+	_, err := tx.Exec(query, name)
+	return err
+}
+
+func (r *RoleRepository) GetAllPermissions(tx *sqlx.Tx) ([]entity.Permission, error) {
+	query := `SELECT * FROM permissions ORDER BY name ASC`
+
+	var permissions []entity.Permission
+	err := tx.Select(&permissions, query)
+
+	return permissions, err
+}
+
+func (r *RoleRepository) GetRolePermissions(tx *sqlx.Tx) ([]entity.RolePermission, error) {
+	query := `SELECT * FROM role_permissions ORDER BY role_name ASC, permission_name ASC`
+
+	var rolePermissions []entity.RolePermission
+	err := tx.Select(&rolePermissions, query)
+
+	return rolePermissions, err
+}
+
+// ReplacePermissions sets the permissions of a role to exactly the given ones
+func (r *RoleRepository) ReplacePermissions(tx *sqlx.Tx, role string, permissions []string) error {
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_name = ?`, role); err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		query := `INSERT IGNORE INTO role_permissions (role_name, permission_name) VALUES (?, ?)`
+		if _, err := tx.Exec(query, role, permission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadRolePermissions reads every grant outside of a transaction to fill the permission cache
+func (r *RoleRepository) LoadRolePermissions(ctx context.Context) ([]entity.RolePermission, error) {
+	var rolePermissions []entity.RolePermission
+	err := r.db.SelectContext(ctx, &rolePermissions, `SELECT * FROM role_permissions`)
+
+	return rolePermissions, err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type TagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) GetAll(tx *sqlx.Tx) ([]entity.Tag, error) {
+	query := `SELECT * FROM tags ORDER BY name ASC`
+
+	var tags []entity.Tag
+	err := tx.Select(&tags, query)
+
+	return tags, err
+}
+
+func (r *TagRepository) GetByID(tx *sqlx.Tx, id string) (*entity.Tag, error) {
+	query := `SELECT * FROM tags WHERE id = ?`
+
+	var tag entity.Tag
+	err := tx.Get(&tag, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepository) GetBySlug(tx *sqlx.Tx, slug string) (*entity.Tag, error) {
+	query := `SELECT * FROM tags WHERE slug = ?`
+
+	var tag entity.Tag
+	err := tx.Get(&tag, query, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// CountByIDs counts how many of the given tags exist, so callers can reject unknown ids
+func (r *TagRepository) CountByIDs(tx *sqlx.Tx, ids []string) (int, error) {
+	query, args, err := sqlx.In(`SELECT COUNT(*) FROM tags WHERE id IN (?)`, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = tx.Get(&count, tx.Rebind(query), args...)
+
+	return count, err
+}
+
+func (r *TagRepository) Create(tx *sqlx.Tx, tag *entity.Tag) error {
+	query := `INSERT INTO tags (id, name, slug, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, tag.ID, tag.Name, tag.Slug, tag.CreatedAt, tag.UpdatedAt)
+	return err
+}
+
+func (r *TagRepository) Update(tx *sqlx.Tx, tag *entity.Tag) error {
+	query := `UPDATE tags SET name = ?, slug = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, tag.Name, tag.Slug, tag.UpdatedAt, tag.ID)
+	return err
+}
+
+func (r *TagRepository) Delete(tx *sqlx.Tx, id string) error {
+	query := `DELETE FROM tags WHERE id = ?`
+	_, err := tx.Exec(query, id)
+	return err
+}
+
+// GetByProductIDs loads the tags of several products at once
+func (r *TagRepository) GetByProductIDs(tx *sqlx.Tx, productIDs []string) ([]entity.ProductTag, error) {
+	if len(productIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT pt.product_id, t.* FROM product_tags pt
+			  JOIN tags t ON t.id = pt.tag_id
+			  WHERE pt.product_id IN (?) ORDER BY t.name ASC`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []entity.ProductTag
+	err = tx.Select(&tags, tx.Rebind(query), args...)
+
+	return tags, err
+}
+
+// ReplaceProductTags sets the tags of a product to exactly the given ones
+func (r *TagRepository) ReplaceProductTags(tx *sqlx.Tx, productID string, tagIDs []string) error {
+	if _, err := tx.Exec(`DELETE FROM product_tags WHERE product_id = ?`, productID); err != nil {
+		return err
+	}
+
+	for _, tagID := range tagIDs {
+		query := `INSERT IGNORE INTO product_tags (product_id, tag_id) VALUES (?, ?)`
+		if _, err := tx.Exec(query, productID, tagID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
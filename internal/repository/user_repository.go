@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
 )
 
 type UserRepository struct {
@@ -55,13 +58,153 @@ func (r *UserRepository) GetByID(db *sqlx.Tx, id string) (*entity.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetFirst(db *sqlx.Tx, user *entity.User) error {
-	query := `SELECT * FROM users LIMIT 1`
+// GetByIDForUpdate reads a user and holds a row lock until the transaction ends
+func (r *UserRepository) GetByIDForUpdate(db *sqlx.Tx, id string) (*entity.User, error) {
+	query := `SELECT * FROM users WHERE id = ? FOR UPDATE`
 
-	err := db.Get(user, query)
+	var user entity.User
+	err := db.Get(&user, query, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePassword(db *sqlx.Tx, id, password string, updatedAt time.Time) error {
+	query := `UPDATE users SET password = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, password, updatedAt, id)
+	return err
+}
+
+func (r *UserRepository) SetEmailVerified(db *sqlx.Tx, id string, verifiedAt time.Time) error {
+	query := `UPDATE users SET email_verified_at = ?, updated_at = ? WHERE id = ? AND email_verified_at IS NULL`
+
+	_, err := db.Exec(query, verifiedAt, verifiedAt, id)
+	return err
+}
+
+// SetTOTPSecret stores a pending secret, it is not trusted until EnableTOTP
+func (r *UserRepository) SetTOTPSecret(db *sqlx.Tx, id, secret string, updatedAt time.Time) error {
+	query := `UPDATE users SET totp_secret = ?, totp_enabled_at = NULL, totp_last_counter = NULL, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, secret, updatedAt, id)
+	return err
+}
+
+func (r *UserRepository) EnableTOTP(db *sqlx.Tx, id string, counter int64, enabledAt time.Time) error {
+	query := `UPDATE users SET totp_enabled_at = ?, totp_last_counter = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, enabledAt, counter, enabledAt, id)
+	return err
+}
+
+func (r *UserRepository) UpdateTOTPCounter(db *sqlx.Tx, id string, counter int64) error {
+	query := `UPDATE users SET totp_last_counter = ? WHERE id = ?`
+
+	_, err := db.Exec(query, counter, id)
+	return err
+}
+
+func (r *UserRepository) DisableTOTP(db *sqlx.Tx, id string, updatedAt time.Time) error {
+	query := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_counter = NULL, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, updatedAt, id)
+	return err
+}
+
+func (r *UserRepository) UpdateProfile(db *sqlx.Tx, entity *entity.User) error {
+	query := `UPDATE users SET name = ?, phone = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, entity.Name, entity.Phone, entity.UpdatedAt, entity.ID)
+	return err
+}
+
+// Anonymize replaces the personal data of a user and leaves a disabled row behind for their orders.
+// The empty password never matches a bcrypt hash, so nobody can sign in to the account again.
+func (r *UserRepository) Anonymize(db *sqlx.Tx, id, email, name string, deletedAt time.Time) error {
+	query := `UPDATE users SET email = ?, name = ?, phone = '', password = '', email_verified_at = NULL,
+			  totp_secret = NULL, totp_enabled_at = NULL, totp_last_counter = NULL,
+			  disabled_at = ?, deleted_at = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, email, name, deletedAt, deletedAt, deletedAt, id)
+	return err
+}
+
+func (r *UserRepository) UpdateRole(db *sqlx.Tx, id, role string, updatedAt time.Time) error {
+	query := `UPDATE users SET role = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, role, updatedAt, id)
+	return err
+}
+
+func (r *UserRepository) CountByRole(db *sqlx.Tx, role string) (int, error) {
+	query := `SELECT COUNT(*) FROM users WHERE role = ?`
+
+	var count int
+	err := db.Get(&count, query, role)
+
+	return count, err
+}
+
+// GetAll lists users matching the email or name search and the role filter
+func (r *UserRepository) GetAll(db *sqlx.Tx, pagination *model.UserPagination) ([]entity.User, int, error) {
+	baseQuery := `SELECT * FROM users WHERE 1=1`
+	countQuery := `SELECT COUNT(*) FROM users WHERE 1=1`
+
+	args := []interface{}{}
+	if pagination.Query != "" {
+		baseQuery += ` AND (email LIKE ? OR name LIKE ?)`
+		countQuery += ` AND (email LIKE ? OR name LIKE ?)`
+		like := "%" + pagination.Query + "%"
+		args = append(args, like, like)
+	}
+	if pagination.Role != "" {
+		baseQuery += ` AND role = ?`
+		countQuery += ` AND role = ?`
+		args = append(args, pagination.Role)
+	}
+
+	baseQuery += ` ORDER BY ` + pagination.Sort + ` ` + pagination.Order
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	baseQuery += ` LIMIT ? OFFSET ?`
+
+	paginationArgs := append(args, pagination.Limit, offset)
+
+	var total int
+	if err := db.Get(&total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.User
+	err := db.Select(&users, baseQuery, paginationArgs...)
+
+	return users, total, err
+}
+
+// SetDisabled disables a user at disabledAt, or enables them again when it is nil
+func (r *UserRepository) SetDisabled(db *sqlx.Tx, id string, disabledAt *time.Time, updatedAt time.Time) error {
+	query := `UPDATE users SET disabled_at = ?, updated_at = ? WHERE id = ?`
+
+	_, err := db.Exec(query, disabledAt, updatedAt, id)
+	return err
+}
+
+// HasOrders reports whether any order belongs to the user, such users cannot be deleted
+func (r *UserRepository) HasOrders(db *sqlx.Tx, id string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM orders WHERE user_id = ?)`
+
+	var exists bool
+	err := db.Get(&exists, query, id)
+
+	return exists, err
+}
+
+func (r *UserRepository) Delete(db *sqlx.Tx, id string) error {
+	query := `DELETE FROM users WHERE id = ?`
+
+	_, err := db.Exec(query, id)
+	return err
 }
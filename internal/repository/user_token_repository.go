@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type UserTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserTokenRepository(db *sqlx.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+func (r *UserTokenRepository) Create(tx *sqlx.Tx, token *entity.UserToken) error {
+	query := `INSERT INTO user_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(query, token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// GetByHashForUpdate reads a token by its hash and holds a row lock so it can only be used once
+func (r *UserTokenRepository) GetByHashForUpdate(tx *sqlx.Tx, tokenHash, purpose string) (*entity.UserToken, error) {
+	query := `SELECT * FROM user_tokens WHERE token_hash = ? AND purpose = ? FOR UPDATE`
+
+	var token entity.UserToken
+	err := tx.Get(&token, query, tokenHash, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *UserTokenRepository) MarkUsed(tx *sqlx.Tx, id string, usedAt time.Time) error {
+	query := `UPDATE user_tokens SET used_at = ? WHERE id = ?`
+	_, err := tx.Exec(query, usedAt, id)
+	return err
+}
+
+// InvalidateByUserID uses up every pending token of a user for a purpose, so only the latest one works
+func (r *UserTokenRepository) InvalidateByUserID(tx *sqlx.Tx, userID, purpose string, usedAt time.Time) error {
+	query := `UPDATE user_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL`
+	_, err := tx.Exec(query, usedAt, userID, purpose)
+	return err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+)
+
+type VariantRepository struct {
+	db *sqlx.DB
+}
+
+func NewVariantRepository(db *sqlx.DB) *VariantRepository {
+	return &VariantRepository{db: db}
+}
+
+func (r *VariantRepository) GetByProductID(tx *sqlx.Tx, productID string) ([]entity.ProductVariant, error) {
+	query := `SELECT * FROM product_variants WHERE product_id = ? ORDER BY price_delta ASC, name ASC`
+
+	var variants []entity.ProductVariant
+	err := tx.Select(&variants, query, productID)
+
+	return variants, err
+}
+
+// CountByProductID tells whether a product is only sold through its variants
+func (r *VariantRepository) CountByProductID(tx *sqlx.Tx, productID string) (int, error) {
+	query := `SELECT COUNT(*) FROM product_variants WHERE product_id = ?`
+
+	var count int
+	err := tx.Get(&count, query, productID)
+
+	return count, err
+}
+
+func (r *VariantRepository) GetByID(tx *sqlx.Tx, id string) (*entity.ProductVariant, error) {
+	query := `SELECT * FROM product_variants WHERE id = ?`
+
+	var variant entity.ProductVariant
+	err := tx.Get(&variant, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *VariantRepository) GetBySKU(tx *sqlx.Tx, sku string) (*entity.ProductVariant, error) {
+	query := `SELECT * FROM product_variants WHERE sku = ?`
+
+	var variant entity.ProductVariant
+	err := tx.Get(&variant, query, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+func (r *VariantRepository) Create(tx *sqlx.Tx, variant *entity.ProductVariant) error {
+	query := `INSERT INTO product_variants (id, product_id, sku, name, price_delta, stock, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := tx.Exec(
+		query,
+		variant.ID,
+		variant.ProductID,
+		variant.SKU,
+		variant.Name,
+		variant.PriceDelta,
+		variant.Stock,
+		variant.CreatedAt,
+		variant.UpdatedAt,
+	)
+	return err
+}
+
+func (r *VariantRepository) Update(tx *sqlx.Tx, variant *entity.ProductVariant) error {
+	query := `UPDATE product_variants SET sku = ?, name = ?, price_delta = ?, stock = ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, variant.SKU, variant.Name, variant.PriceDelta, variant.Stock, variant.UpdatedAt, variant.ID)
+	return err
+}
+
+func (r *VariantRepository) Delete(tx *sqlx.Tx, id string) error {
+	query := `DELETE FROM product_variants WHERE id = ?`
+	_, err := tx.Exec(query, id)
+	return err
+}
+
+// DecrementStock subtracts quantity only when enough stock is left and returns the affected rows
+func (r *VariantRepository) DecrementStock(tx *sqlx.Tx, id string, quantity int) (int64, error) {
+	query := `UPDATE product_variants SET stock = stock - ?, updated_at = ? WHERE id = ? AND stock >= ?`
+
+	result, err := tx.Exec(query, quantity, time.Now(), id, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// IncrementStock returns quantity to the variant, e.g. when an order is cancelled
+func (r *VariantRepository) IncrementStock(tx *sqlx.Tx, id string, quantity int) error {
+	query := `UPDATE product_variants SET stock = stock + ?, updated_at = ? WHERE id = ?`
+
+	_, err := tx.Exec(query, quantity, time.Now(), id)
+	return err
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+type AddressService struct {
+	AddressRepository *repository.AddressRepository
+	DB                *sqlx.DB
+	Log               *logrus.Logger
+	Validate          *validator.Validate
+}
+
+func NewAddressService(
+	addressRepo *repository.AddressRepository,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *AddressService {
+	return &AddressService{
+		AddressRepository: addressRepo,
+		DB:                db,
+		Log:               log,
+		Validate:          validate,
+	}
+}
+
+func (s *AddressService) GetAll(ctx context.Context) (*model.SuccessResponse[[]*model.AddressResponse], error) {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	addresses, err := s.AddressRepository.GetAllByUserID(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting addresses: %v", err)
+		return nil, err
+	}
+
+	addressResponses := make([]*model.AddressResponse, len(addresses))
+	for i := range addresses {
+		addressResponses[i] = toAddressResponse(&addresses[i])
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[[]*model.AddressResponse]{
+		Data: &addressResponses,
+	}, nil
+}
+
+func (s *AddressService) GetByID(ctx context.Context, request *model.GetAddressRequest) (*model.SuccessResponse[*model.AddressResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	address, err := s.getOwnAddress(tx, request.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	addressResponse := toAddressResponse(address)
+	return &model.SuccessResponse[*model.AddressResponse]{
+		Data: &addressResponse,
+	}, nil
+}
+
+func (s *AddressService) Create(ctx context.Context, request *model.AddressRequest) (*model.SuccessResponse[*model.AddressResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	existing, err := s.AddressRepository.GetAllByUserID(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting addresses: %v", err)
+		return nil, err
+	}
+
+	// The first address of a user is always the default one
+	isDefault := request.IsDefault || len(existing) == 0
+	if isDefault {
+		if err = s.AddressRepository.ClearDefault(tx, userID); err != nil {
+			s.Log.Errorf("error clearing default address: %v", err)
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	address := &entity.Address{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		AddressLine: request.AddressLine,
+		City:        request.City,
+		State:       request.State,
+		PostalCode:  request.PostalCode,
+		Country:     request.Country,
+		IsDefault:   isDefault,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = s.AddressRepository.Create(tx, address); err != nil {
+		s.Log.Errorf("error creating address: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	addressResponse := toAddressResponse(address)
+	return &model.SuccessResponse[*model.AddressResponse]{
+		Data: &addressResponse,
+	}, nil
+}
+
+func (s *AddressService) Update(ctx context.Context, request *model.UpdateAddressRequest) (*model.SuccessResponse[*model.AddressResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	address, err := s.getOwnAddress(tx, request.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only update fields that are provided in the request
+	if request.AddressLine != nil {
+		address.AddressLine = *request.AddressLine
+	}
+	if request.City != nil {
+		address.City = *request.City
+	}
+	if request.State != nil {
+		address.State = *request.State
+	}
+	if request.PostalCode != nil {
+		address.PostalCode = *request.PostalCode
+	}
+	if request.Country != nil {
+		address.Country = *request.Country
+	}
+
+	// A default address can only be replaced by marking another one as default
+	if request.IsDefault != nil && *request.IsDefault && !address.IsDefault {
+		if err = s.AddressRepository.ClearDefault(tx, userID); err != nil {
+			s.Log.Errorf("error clearing default address: %v", err)
+			return nil, err
+		}
+		address.IsDefault = true
+	}
+	address.UpdatedAt = time.Now()
+
+	if err = s.AddressRepository.Update(tx, address); err != nil {
+		s.Log.Errorf("error updating address: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	addressResponse := toAddressResponse(address)
+	return &model.SuccessResponse[*model.AddressResponse]{
+		Data: &addressResponse,
+	}, nil
+}
+
+func (s *AddressService) SetDefault(ctx context.Context, request *model.GetAddressRequest) (*model.SuccessResponse[*model.AddressResponse], error) {
+	isDefault := true
+	return s.Update(ctx, &model.UpdateAddressRequest{
+		ID:        request.ID,
+		IsDefault: &isDefault,
+	})
+}
+
+func (s *AddressService) Delete(ctx context.Context, request *model.GetAddressRequest) (*model.SuccessResponse[*model.GetAddressRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	address, err := s.getOwnAddress(tx, request.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders keep a snapshot of the address, so deleting it never changes order history
+	if err = s.AddressRepository.Delete(tx, address.ID, userID); err != nil {
+		s.Log.Errorf("error deleting address: %v", err)
+		if helper.IsForeignKeyViolation(err) {
+			err = e.ErrAddressInUse
+		}
+		return nil, err
+	}
+
+	// Promote the next address so the user keeps a default one
+	if address.IsDefault {
+		var next *entity.Address
+		next, err = s.AddressRepository.GetDefaultByUserID(tx, userID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			err = nil
+		case err != nil:
+			s.Log.Errorf("error getting next default address: %v", err)
+			return nil, err
+		default:
+			next.IsDefault = true
+			next.UpdatedAt = time.Now()
+			if err = s.AddressRepository.Update(tx, next); err != nil {
+				s.Log.Errorf("error promoting default address: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.GetAddressRequest]{
+		Data: &request,
+	}, nil
+}
+
+// getOwnAddress reads an address of the user, hiding addresses of other users
+func (s *AddressService) getOwnAddress(tx *sqlx.Tx, id, userID string) (*entity.Address, error) {
+	address, err := s.AddressRepository.GetByIDAndUserID(tx, id, userID)
+	if err != nil {
+		s.Log.Errorf("error getting address: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func toAddressResponse(address *entity.Address) *model.AddressResponse {
+	return &model.AddressResponse{
+		ID:          address.ID,
+		UserID:      address.UserID,
+		AddressLine: address.AddressLine,
+		City:        address.City,
+		State:       address.State,
+		PostalCode:  address.PostalCode,
+		Country:     address.Country,
+		IsDefault:   address.IsDefault,
+		CreatedAt:   helper.FormatTime(address.CreatedAt),
+		UpdatedAt:   helper.FormatTime(address.UpdatedAt),
+	}
+}
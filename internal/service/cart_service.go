@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+type CartService struct {
+	CartRepository    *repository.CartRepository
+	ProductRepository *repository.ProductRepository
+	OrderService      *OrderService
+	DB                *sqlx.DB
+	Log               *logrus.Logger
+	Validate          *validator.Validate
+}
+
+func NewCartService(
+	cartRepo *repository.CartRepository,
+	productRepo *repository.ProductRepository,
+	orderService *OrderService,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *CartService {
+	return &CartService{
+		CartRepository:    cartRepo,
+		ProductRepository: productRepo,
+		OrderService:      orderService,
+		DB:                db,
+		Log:               log,
+		Validate:          validate,
+	}
+}
+
+func (s *CartService) Get(ctx context.Context) (*model.SuccessResponse[*model.CartResponse], error) {
+	return s.withCart(ctx, func(tx *sqlx.Tx, cart *entity.Cart) error {
+		return nil
+	})
+}
+
+func (s *CartService) AddItem(ctx context.Context, request *model.AddCartItemRequest) (*model.SuccessResponse[*model.CartResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	return s.withCart(ctx, func(tx *sqlx.Tx, cart *entity.Cart) error {
+		product, err := s.ProductRepository.GetActiveByID(tx, request.ProductID)
+		if err != nil {
+			s.Log.Errorf("error getting product: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return e.ErrNotFound
+			}
+			return err
+		}
+
+		// The line is checked like checkout would, so a cart never holds a selection that cannot be ordered
+		if _, _, err := s.OrderService.priceLine(tx, product, request.VariantID, request.OptionIDs); err != nil {
+			return err
+		}
+
+		optionIDs := slices.Clone(request.OptionIDs)
+		slices.Sort(optionIDs)
+
+		now := time.Now()
+		item := &entity.CartItem{
+			ID:        uuid.NewString(),
+			CartID:    cart.ID,
+			ProductID: request.ProductID,
+			LineKey:   cartLineKey(request.VariantID, optionIDs),
+			Quantity:  request.Quantity,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if request.VariantID != "" {
+			item.VariantID = &request.VariantID
+		}
+
+		inserted, err := s.CartRepository.AddItem(tx, item)
+		if err != nil {
+			s.Log.Errorf("error adding cart item: %v", err)
+			return err
+		}
+
+		// A line that already existed keeps the add-ons it was created with, they are the same ones
+		if inserted {
+			for _, optionID := range optionIDs {
+				if err := s.CartRepository.CreateItemOption(tx, &entity.CartItemOption{CartItemID: item.ID, OptionID: optionID}); err != nil {
+					s.Log.Errorf("error adding cart item option: %v", err)
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, request *model.UpdateCartItemRequest) (*model.SuccessResponse[*model.CartResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	return s.withCart(ctx, func(tx *sqlx.Tx, cart *entity.Cart) error {
+		item := &entity.CartItem{
+			ID:        request.ID,
+			CartID:    cart.ID,
+			Quantity:  request.Quantity,
+			UpdatedAt: time.Now(),
+		}
+
+		affected, err := s.CartRepository.UpdateItem(tx, item)
+		if err != nil {
+			s.Log.Errorf("error updating cart item: %v", err)
+			return err
+		}
+		if affected == 0 {
+			return e.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, request *model.DeleteCartItemRequest) (*model.SuccessResponse[*model.CartResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	return s.withCart(ctx, func(tx *sqlx.Tx, cart *entity.Cart) error {
+		affected, err := s.CartRepository.DeleteItem(tx, cart.ID, request.ID)
+		if err != nil {
+			s.Log.Errorf("error deleting cart item: %v", err)
+			return err
+		}
+		if affected == 0 {
+			return e.ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+func (s *CartService) Clear(ctx context.Context) (*model.SuccessResponse[*model.CartResponse], error) {
+	return s.withCart(ctx, func(tx *sqlx.Tx, cart *entity.Cart) error {
+		if err := s.CartRepository.ClearItems(tx, cart.ID); err != nil {
+			s.Log.Errorf("error clearing cart: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// Checkout turns the cart of the current user into an order and empties the cart in one transaction
+func (s *CartService) Checkout(ctx context.Context, request *model.CheckoutCartRequest) (*model.SuccessResponse[*model.OrderResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	cart, err := s.getOrCreateCart(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cartItems, err := s.getItems(tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cartItems) == 0 {
+		err = e.ErrCartEmpty
+		return nil, err
+	}
+
+	items := make([]model.OrderItemRequest, len(cartItems))
+	for i, item := range cartItems {
+		items[i] = model.OrderItemRequest{
+			ProductID: item.ProductID,
+			OptionIDs: item.OptionIDs,
+			Quantity:  item.Quantity,
+		}
+		if item.VariantID != nil {
+			items[i].VariantID = *item.VariantID
+		}
+	}
+
+	order, err := s.OrderService.placeOrder(tx, userID, request.AddressID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.CartRepository.ClearItems(tx, cart.ID); err != nil {
+		s.Log.Errorf("error clearing cart: %v", err)
+		return nil, err
+	}
+
+	orderResponse, err := s.OrderService.toOrderResponse(tx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.OrderResponse]{
+		Data: &orderResponse,
+	}, nil
+}
+
+// withCart runs fn against the locked cart of the current user and returns the resulting cart
+func (s *CartService) withCart(ctx context.Context, fn func(tx *sqlx.Tx, cart *entity.Cart) error) (*model.SuccessResponse[*model.CartResponse], error) {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	cart, err := s.getOrCreateCart(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = fn(tx, cart); err != nil {
+		return nil, err
+	}
+
+	cartResponse, err := s.toCartResponse(tx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.CartResponse]{
+		Data: &cartResponse,
+	}, nil
+}
+
+// getOrCreateCart returns the locked cart of a user, creating an empty one on first use
+func (s *CartService) getOrCreateCart(tx *sqlx.Tx, userID string) (*entity.Cart, error) {
+	now := time.Now()
+	cart := &entity.Cart{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.CartRepository.Create(tx, cart); err != nil {
+		s.Log.Errorf("error creating cart: %v", err)
+		return nil, err
+	}
+
+	cart, err := s.CartRepository.GetByUserIDForUpdate(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting cart: %v", err)
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+// getItems loads the lines of a cart with their add-ons
+func (s *CartService) getItems(tx *sqlx.Tx, cartID string) ([]entity.CartItem, error) {
+	items, err := s.CartRepository.GetItems(tx, cartID)
+	if err != nil {
+		s.Log.Errorf("error getting cart items: %v", err)
+		return nil, err
+	}
+
+	options, err := s.CartRepository.GetItemOptions(tx, cartID)
+	if err != nil {
+		s.Log.Errorf("error getting cart item options: %v", err)
+		return nil, err
+	}
+
+	optionsByItem := make(map[string][]string, len(items))
+	for _, option := range options {
+		optionsByItem[option.CartItemID] = append(optionsByItem[option.CartItemID], option.OptionID)
+	}
+
+	for i := range items {
+		items[i].OptionIDs = optionsByItem[items[i].ID]
+	}
+
+	return items, nil
+}
+
+// toCartResponse prices every line with the live product, variant and add-on data the way checkout
+// would and flags lines that cannot be fulfilled
+func (s *CartService) toCartResponse(tx *sqlx.Tx, cart *entity.Cart) (*model.CartResponse, error) {
+	items, err := s.getItems(tx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.CartResponse{
+		ID:          cart.ID,
+		Items:       make([]model.CartItemResponse, len(items)),
+		CanCheckout: len(items) > 0,
+		UpdatedAt:   helper.FormatTime(cart.UpdatedAt),
+	}
+
+	for i, item := range items {
+		product, err := s.ProductRepository.GetByID(tx, item.ProductID)
+		if err != nil {
+			s.Log.Errorf("error getting product for cart item %s: %v", item.ID, err)
+			return nil, err
+		}
+
+		response.Items[i] = model.CartItemResponse{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Name:      product.Name,
+			Image:     product.Image,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			UnitPrice: product.Price,
+			Stock:     product.Stock,
+		}
+
+		var variantID string
+		if item.VariantID != nil {
+			variantID = *item.VariantID
+		}
+
+		priced, variant, err := s.OrderService.priceLine(tx, product, variantID, item.OptionIDs)
+		switch {
+		case errors.Is(err, e.ErrVariantRequired), errors.Is(err, e.ErrVariantNotFound), errors.Is(err, e.ErrInvalidOptions):
+			// The catalog changed since the line was added, it has to be removed and added again
+			response.Items[i].Warning = "selected options are no longer available"
+		case err != nil:
+			return nil, err
+		default:
+			response.Items[i].VariantName = priced.VariantName
+			response.Items[i].UnitPrice = priced.UnitPrice
+			if variant != nil {
+				response.Items[i].Stock = variant.Stock
+			}
+			for _, option := range priced.Options {
+				response.Items[i].Options = append(response.Items[i].Options, model.CartItemOptionResponse{
+					OptionID:   *option.OptionID,
+					GroupName:  option.GroupName,
+					OptionName: option.OptionName,
+					Price:      option.Price,
+				})
+			}
+			response.Items[i].Warning = stockWarning(response.Items[i].Stock, item.Quantity)
+		}
+		if !product.Available() {
+			response.Items[i].Warning = "no longer available"
+		}
+
+		response.Items[i].Subtotal = response.Items[i].UnitPrice * float64(item.Quantity)
+		response.TotalPrice += response.Items[i].Subtotal
+
+		if response.Items[i].Warning != "" {
+			response.CanCheckout = false
+		}
+	}
+
+	return response, nil
+}
+
+// cartLineKey identifies the variant and add-ons of a line, a plain product has an empty key
+func cartLineKey(variantID string, sortedOptionIDs []string) string {
+	if variantID == "" && len(sortedOptionIDs) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(variantID + "|" + strings.Join(sortedOptionIDs, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+func stockWarning(stock, quantity int) string {
+	switch {
+	case stock <= 0:
+		return "out of stock"
+	case stock < quantity:
+		return fmt.Sprintf("only %d left in stock", stock)
+	default:
+		return ""
+	}
+}
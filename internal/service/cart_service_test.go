@@ -0,0 +1,33 @@
+package service
+
+import "testing"
+
+func TestCartLineKey(t *testing.T) {
+	plain := cartLineKey("", nil)
+	if plain != "" {
+		t.Errorf("plain product key = %q, want an empty key like existing lines", plain)
+	}
+
+	keys := map[string]string{
+		"variant":             cartLineKey("variant-1", nil),
+		"other variant":       cartLineKey("variant-2", nil),
+		"options":             cartLineKey("", []string{"option-1", "option-2"}),
+		"variant and options": cartLineKey("variant-1", []string{"option-1", "option-2"}),
+		"fewer options":       cartLineKey("variant-1", []string{"option-1"}),
+	}
+
+	seen := make(map[string]string, len(keys))
+	for name, key := range keys {
+		if len(key) != 64 {
+			t.Errorf("%s key has length %d, want 64 to fit line_key", name, len(key))
+		}
+		if other, ok := seen[key]; ok {
+			t.Errorf("%s and %s share the line key %s", name, other, key)
+		}
+		seen[key] = name
+	}
+
+	if cartLineKey("variant-1", []string{"option-1", "option-2"}) != keys["variant and options"] {
+		t.Error("the same selection produced another line key")
+	}
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/sirupsen/logrus"
+)
+
+type CategoryService struct {
+	CategoryRepository *repository.CategoryRepository
+	DB                 *sqlx.DB
+	Log                *logrus.Logger
+	Validate           *validator.Validate
+}
+
+func NewCategoryService(
+	categoryRepo *repository.CategoryRepository,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *CategoryService {
+	return &CategoryService{
+		CategoryRepository: categoryRepo,
+		DB:                 db,
+		Log:                log,
+		Validate:           validate,
+	}
+}
+
+// GetAll returns the categories as a tree, top level categories first
+func (s *CategoryService) GetAll(ctx context.Context) (*model.SuccessResponse[[]*model.CategoryResponse], error) {
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	categories, err := s.CategoryRepository.GetAll(tx)
+	if err != nil {
+		s.Log.Errorf("error getting categories: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	responses := make(map[string]*model.CategoryResponse, len(categories))
+	for i := range categories {
+		responses[categories[i].ID] = toCategoryResponse(&categories[i])
+	}
+
+	roots := []*model.CategoryResponse{}
+	for i := range categories {
+		response := responses[categories[i].ID]
+		if parent, ok := responses[derefString(categories[i].ParentID)]; ok {
+			parent.Children = append(parent.Children, response)
+			continue
+		}
+		roots = append(roots, response)
+	}
+
+	return &model.SuccessResponse[[]*model.CategoryResponse]{
+		Data: &roots,
+	}, nil
+}
+
+func (s *CategoryService) Create(ctx context.Context, request *model.CreateCategoryRequest) (*model.SuccessResponse[*model.CategoryResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	slug, err := resolveSlug(request.Slug, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if request.ParentID != nil {
+		if _, err = s.getCategory(tx, *request.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.checkSlug(tx, slug, ""); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	data := &entity.Category{
+		ID:          uuid.NewString(),
+		ParentID:    request.ParentID,
+		Name:        request.Name,
+		Slug:        slug,
+		Description: request.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = s.CategoryRepository.Create(tx, data); err != nil {
+		s.Log.Errorf("error creating category: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	categoryResponse := toCategoryResponse(data)
+	return &model.SuccessResponse[*model.CategoryResponse]{
+		Data: &categoryResponse,
+	}, nil
+}
+
+func (s *CategoryService) Update(ctx context.Context, request *model.UpdateCategoryRequest) (*model.SuccessResponse[*model.CategoryResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getCategory(tx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only update fields that are provided in the request
+	if request.Name != nil {
+		data.Name = *request.Name
+	}
+	if request.Description != nil {
+		data.Description = *request.Description
+	}
+	if request.Slug != nil {
+		if data.Slug, err = resolveSlug(*request.Slug, data.Name); err != nil {
+			return nil, err
+		}
+		if err = s.checkSlug(tx, data.Slug, data.ID); err != nil {
+			return nil, err
+		}
+	}
+	if request.ParentID != nil {
+		data.ParentID = nil
+		if *request.ParentID != "" {
+			if err = s.checkParent(tx, data.ID, *request.ParentID); err != nil {
+				return nil, err
+			}
+			data.ParentID = request.ParentID
+		}
+	}
+	data.UpdatedAt = time.Now()
+
+	if err = s.CategoryRepository.Update(tx, data); err != nil {
+		s.Log.Errorf("error updating category: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	categoryResponse := toCategoryResponse(data)
+	return &model.SuccessResponse[*model.CategoryResponse]{
+		Data: &categoryResponse,
+	}, nil
+}
+
+// Delete removes a category without subcategories, its products become uncategorized
+func (s *CategoryService) Delete(ctx context.Context, request *model.GetCategoryRequest) (*model.SuccessResponse[*model.GetCategoryRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.getCategory(tx, request.ID); err != nil {
+		return nil, err
+	}
+
+	hasChildren, err := s.CategoryRepository.HasChildren(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error checking subcategories: %v", err)
+		return nil, err
+	}
+	if hasChildren {
+		err = e.ErrCategoryInUse
+		return nil, err
+	}
+
+	if err = s.CategoryRepository.Delete(tx, request.ID); err != nil {
+		s.Log.Errorf("error deleting category: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.GetCategoryRequest]{
+		Data: &request,
+	}, nil
+}
+
+func (s *CategoryService) getCategory(tx *sqlx.Tx, id string) (*entity.Category, error) {
+	category, err := s.CategoryRepository.GetByID(tx, id)
+	if err != nil {
+		s.Log.Errorf("error getting category: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// checkSlug rejects a slug taken by another category than id
+func (s *CategoryService) checkSlug(tx *sqlx.Tx, slug, id string) error {
+	existing, err := s.CategoryRepository.GetBySlug(tx, slug)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		s.Log.Errorf("error getting category by slug: %v", err)
+		return err
+	case existing.ID != id:
+		return e.ErrSlugExists
+	}
+
+	return nil
+}
+
+// checkParent refuses a parent that would turn the tree into a cycle
+func (s *CategoryService) checkParent(tx *sqlx.Tx, id, parentID string) error {
+	if _, err := s.getCategory(tx, parentID); err != nil {
+		return err
+	}
+
+	categories, err := s.CategoryRepository.GetAll(tx)
+	if err != nil {
+		s.Log.Errorf("error getting categories: %v", err)
+		return err
+	}
+
+	for _, descendant := range categorySubtree(categories, id) {
+		if descendant == parentID {
+			return e.ErrInvalidCategoryParent
+		}
+	}
+
+	return nil
+}
+
+// resolveSlug uses the given slug, or derives one from the name, and rejects slugs that are not canonical
+func resolveSlug(slug, name string) (string, error) {
+	if slug == "" {
+		slug = helper.Slugify(name)
+	}
+	if slug == "" || helper.Slugify(slug) != slug {
+		return "", e.ErrValidation
+	}
+
+	return slug, nil
+}
+
+// categorySubtree returns rootID followed by the ids of all of its descendants
+func categorySubtree(categories []entity.Category, rootID string) []string {
+	children := make(map[string][]string)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []string{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	return ids
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func toCategoryResponse(category *entity.Category) *model.CategoryResponse {
+	return &model.CategoryResponse{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		CreatedAt:   helper.FormatTime(category.CreatedAt),
+		UpdatedAt:   helper.FormatTime(category.UpdatedAt),
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	e "github.com/savioruz/bake/pkg/error"
+)
+
+// loginAttemptKeys returns the throttled keys of a login, the email key first
+func loginAttemptKeys(email, ip string) []string {
+	keys := []string{"email:" + strings.ToLower(email)}
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	return keys
+}
+
+// checkLoginThrottle rejects a login while any of its keys is locked
+func (s *UserService) checkLoginThrottle(ctx context.Context, keys []string) error {
+	attempts, err := s.LoginAttemptRepository.GetByKeys(ctx, keys)
+	if err != nil {
+		s.Log.Errorf("error getting login attempts: %v", err)
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, attempt := range attempts {
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			wait = max(wait, attempt.LockedUntil.Sub(now))
+		}
+	}
+	if wait > 0 {
+		return &e.LockedError{RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the email and the IP. An email backs off
+// exponentially and is locked out after LoginMaxAttempts failures. An IP is only locked out
+// after LoginMaxAttemptsPerIP failures so users behind a shared address are not slowed down.
+func (s *UserService) recordLoginFailure(ctx context.Context, keys []string) {
+	for i, key := range keys {
+		attempt, err := s.LoginAttemptRepository.RecordFailure(ctx, key, s.Config.LoginLockout)
+		if err != nil {
+			s.Log.Errorf("error recording login failure: %v", err)
+			continue
+		}
+
+		var delay time.Duration
+		if i == 0 {
+			delay = loginBackoff(attempt.Failures, s.Config.LoginMaxAttempts, s.Config.LoginBackoffBase, s.Config.LoginLockout)
+		} else if attempt.Failures >= s.Config.LoginMaxAttemptsPerIP {
+			delay = s.Config.LoginLockout
+		}
+		if delay == 0 {
+			continue
+		}
+
+		if delay == s.Config.LoginLockout {
+			s.Log.Warnf("locking %s for %s after %d failed logins", key, delay, attempt.Failures)
+		}
+		if err := s.LoginAttemptRepository.Lock(ctx, key, time.Now().Add(delay)); err != nil {
+			s.Log.Errorf("error locking login attempts: %v", err)
+		}
+	}
+}
+
+// resetLoginAttempts clears the email counter after a successful login. The IP counter is left to
+// expire, otherwise an attacker could reset it by logging into an account of their own in between.
+func (s *UserService) resetLoginAttempts(ctx context.Context, keys []string) {
+	if err := s.LoginAttemptRepository.DeleteByKeys(ctx, keys[:1]); err != nil {
+		s.Log.Errorf("error resetting login attempts: %v", err)
+	}
+}
+
+// loginBackoff doubles the wait with every failure, capped by the lockout
+func loginBackoff(failures, maxAttempts int, base, lockout time.Duration) time.Duration {
+	if failures >= maxAttempts {
+		return lockout
+	}
+
+	delay := base << (failures - 1)
+	if delay <= 0 || delay > lockout {
+		return lockout
+	}
+	return delay
+}
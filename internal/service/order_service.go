@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -12,33 +16,58 @@ import (
 	"github.com/savioruz/bake/internal/repository"
 	e "github.com/savioruz/bake/pkg/error"
 	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/savioruz/bake/pkg/payment"
 	"github.com/sirupsen/logrus"
 )
 
 type OrderService struct {
-	OrderRepository   *repository.OrderRepository
-	ProductRepository *repository.ProductRepository
-	AddressRepository *repository.AddressRepository
+	OrderRepository     *repository.OrderRepository
+	OrderItemRepository *repository.OrderItemRepository
+	ProductRepository   *repository.ProductRepository
+	VariantRepository   *repository.VariantRepository
+	OptionRepository    *repository.OptionRepository
+	AddressRepository   *repository.AddressRepository
+	UserRepository      *repository.UserRepository
+	// PaymentRepository and Gateway refund captured payments when a paid order is cancelled or refunded
+	PaymentRepository *repository.PaymentRepository
+	Gateway           payment.PaymentGateway
 	DB                *sqlx.DB
 	Log               *logrus.Logger
 	Validate          *validator.Validate
+	// RequireVerifiedEmail blocks orders from users that have not verified their email
+	RequireVerifiedEmail bool
 }
 
 func NewOrderService(
 	orderRepo *repository.OrderRepository,
+	orderItemRepo *repository.OrderItemRepository,
 	productRepo *repository.ProductRepository,
+	variantRepo *repository.VariantRepository,
+	optionRepo *repository.OptionRepository,
 	addressRepo *repository.AddressRepository,
+	userRepo *repository.UserRepository,
+	paymentRepo *repository.PaymentRepository,
+	gateway payment.PaymentGateway,
 	db *sqlx.DB,
 	log *logrus.Logger,
 	validate *validator.Validate,
+	requireVerifiedEmail bool,
 ) *OrderService {
 	return &OrderService{
-		OrderRepository:   orderRepo,
-		ProductRepository: productRepo,
-		AddressRepository: addressRepo,
-		DB:                db,
-		Log:               log,
-		Validate:          validate,
+		OrderRepository:      orderRepo,
+		OrderItemRepository:  orderItemRepo,
+		ProductRepository:    productRepo,
+		VariantRepository:    variantRepo,
+		OptionRepository:     optionRepo,
+		AddressRepository:    addressRepo,
+		UserRepository:       userRepo,
+		PaymentRepository:    paymentRepo,
+		Gateway:              gateway,
+		DB:                   db,
+		Log:                  log,
+		Validate:             validate,
+		RequireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
@@ -48,6 +77,11 @@ func (s *OrderService) Create(ctx context.Context, request *model.CreateOrderReq
 		return nil, e.ErrValidation
 	}
 
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
 	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		s.Log.Errorf("error beginning transaction: %v", err)
@@ -61,40 +95,13 @@ func (s *OrderService) Create(ctx context.Context, request *model.CreateOrderReq
 		}
 	}()
 
-	address, err := s.AddressRepository.GetByUserID(tx, request.UserID)
+	order, err := s.placeOrder(tx, userID, request.AddressID, request.Items)
 	if err != nil {
-		s.Log.Errorf("error getting user address: %v", err)
 		return nil, err
 	}
 
-	product, err := s.ProductRepository.GetByID(tx, request.ProductID)
+	orderResponse, err := s.toOrderResponse(tx, order)
 	if err != nil {
-		s.Log.Errorf("error getting product: %v", err)
-		return nil, err
-	}
-
-	if product.Stock < request.Quantity {
-		s.Log.Error("insufficient stock")
-		return nil, e.ErrInsufficientStock
-	}
-
-	totalPrice := product.Price * float64(request.Quantity)
-
-	now := time.Now()
-	order := &entity.Order{
-		ID:         uuid.NewString(),
-		UserID:     request.UserID,
-		ProductID:  request.ProductID,
-		AddressID:  address.ID,
-		Quantity:   request.Quantity,
-		TotalPrice: totalPrice,
-		Status:     "PENDING",
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
-
-	if err := s.OrderRepository.Create(tx, order); err != nil {
-		s.Log.Errorf("error creating order: %v", err)
 		return nil, err
 	}
 
@@ -103,18 +110,6 @@ func (s *OrderService) Create(ctx context.Context, request *model.CreateOrderReq
 		return nil, err
 	}
 
-	orderResponse := &model.OrderResponse{
-		ID:         order.ID,
-		ProductID:  order.ProductID,
-		Quantity:   order.Quantity,
-		TotalPrice: order.TotalPrice,
-		Status:     order.Status,
-		CreatedAt:  helper.FormatTime(order.CreatedAt),
-		UpdatedAt:  helper.FormatTime(order.UpdatedAt),
-		Product:    *product,
-		Address:    *address,
-	}
-
 	return &model.SuccessResponse[*model.OrderResponse]{
 		Data: &orderResponse,
 	}, nil
@@ -126,6 +121,17 @@ func (s *OrderService) GetAll(ctx context.Context, request *model.OrderPaginatio
 		return nil, e.ErrValidation
 	}
 
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	// Staff with orders:read_all see every order, everyone else only their own
+	scope := userID
+	if canReadAllOrders(ctx) {
+		scope = ""
+	}
+
 	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		s.Log.Errorf("error beginning transaction: %v", err)
@@ -139,39 +145,41 @@ func (s *OrderService) GetAll(ctx context.Context, request *model.OrderPaginatio
 		}
 	}()
 
-	orders, total, err := s.OrderRepository.GetAll(tx, request)
+	orders, total, err := s.OrderRepository.GetAll(tx, scope, request)
 	if err != nil {
 		s.Log.Errorf("error getting all orders: %v", err)
 		return nil, err
 	}
 
-	orderResponses := make([]*model.OrderResponse, len(orders))
+	orderIDs := make([]string, len(orders))
 	for i, order := range orders {
-		product, err := s.ProductRepository.GetByID(tx, order.ProductID)
-		if err != nil {
-			s.Log.Errorf("error getting product for order %s: %v", order.ID, err)
-			return nil, err
-		}
+		orderIDs[i] = order.ID
+	}
+
+	items, err := s.OrderItemRepository.GetByOrderIDs(tx, orderIDs)
+	if err != nil {
+		s.Log.Errorf("error getting order items: %v", err)
+		return nil, err
+	}
+
+	if err = s.attachOptions(tx, items); err != nil {
+		return nil, err
+	}
+
+	itemsByOrder := make(map[string][]entity.OrderItem, len(orders))
+	for _, item := range items {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+
+	orderResponses := make([]*model.OrderResponse, len(orders))
+	for i := range orders {
+		order := &orders[i]
+		order.Items = itemsByOrder[order.ID]
 
-		address, err := s.AddressRepository.GetByUserID(tx, order.UserID)
+		orderResponses[i], err = s.toOrderResponse(tx, order)
 		if err != nil {
-			s.Log.Errorf("error getting address for order %s: %v", order.ID, err)
 			return nil, err
 		}
-
-		orderResponses[i] = &model.OrderResponse{
-			ID:         order.ID,
-			UserID:     order.UserID,
-			ProductID:  order.ProductID,
-			AddressID:  order.AddressID,
-			Quantity:   order.Quantity,
-			TotalPrice: order.TotalPrice,
-			Status:     order.Status,
-			CreatedAt:  helper.FormatTime(order.CreatedAt),
-			UpdatedAt:  helper.FormatTime(order.UpdatedAt),
-			Product:    *product,
-			Address:    *address,
-		}
 	}
 
 	response := model.SuccessResponse[[]*model.OrderResponse]{
@@ -198,6 +206,11 @@ func (s *OrderService) GetById(ctx context.Context, request *model.GetOrderReque
 		return nil, e.ErrValidation
 	}
 
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
 	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		s.Log.Errorf("error beginning transaction: %v", err)
@@ -214,33 +227,69 @@ func (s *OrderService) GetById(ctx context.Context, request *model.GetOrderReque
 	order, err := s.OrderRepository.GetByID(tx, request.ID)
 	if err != nil {
 		s.Log.Errorf("error getting order by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
 		return nil, err
 	}
 
-	product, err := s.ProductRepository.GetByID(tx, order.ProductID)
+	if !canAccessOrder(ctx, order) {
+		s.Log.Warnf("user %s tried to read order %s of another user", userID, order.ID)
+		err = e.ErrNotFound
+		return nil, err
+	}
+
+	orderResponse, err := s.toOrderResponse(tx, order)
 	if err != nil {
-		s.Log.Errorf("error getting product by id: %v", err)
 		return nil, err
 	}
 
-	address, err := s.AddressRepository.GetByUserID(tx, order.UserID)
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.OrderResponse]{
+		Data: &orderResponse,
+	}, nil
+}
+
+// UpdateStatus moves an order to a new status following the order lifecycle
+func (s *OrderService) UpdateStatus(ctx context.Context, request *model.UpdateOrderStatusRequest) (*model.SuccessResponse[*model.OrderResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		s.Log.Errorf("error getting address by user id: %v", err)
+		s.Log.Errorf("error beginning transaction: %v", err)
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
 
-	orderResponse := &model.OrderResponse{
-		ID:         order.ID,
-		UserID:     order.UserID,
-		ProductID:  order.ProductID,
-		AddressID:  order.AddressID,
-		Quantity:   order.Quantity,
-		TotalPrice: order.TotalPrice,
-		Status:     order.Status,
-		CreatedAt:  helper.FormatTime(order.CreatedAt),
-		UpdatedAt:  helper.FormatTime(order.UpdatedAt),
-		Product:    *product,
-		Address:    *address,
+	order, err := s.OrderRepository.GetByIDForUpdate(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting order by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err = s.transition(ctx, tx, order, request.Status); err != nil {
+		return nil, err
+	}
+
+	orderResponse, err := s.toOrderResponse(tx, order)
+	if err != nil {
+		return nil, err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -252,3 +301,620 @@ func (s *OrderService) GetById(ctx context.Context, request *model.GetOrderReque
 		Data: &orderResponse,
 	}, nil
 }
+
+// Cancel cancels an order on behalf of its owner and returns the reserved stock
+func (s *OrderService) Cancel(ctx context.Context, request *model.GetOrderRequest) (*model.SuccessResponse[*model.OrderResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	order, err := s.OrderRepository.GetByIDForUpdate(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting order by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if !canAccessOrder(ctx, order) {
+		s.Log.Warnf("user %s tried to cancel order %s of another user", userID, order.ID)
+		err = e.ErrNotFound
+		return nil, err
+	}
+
+	if err = s.transition(ctx, tx, order, OrderStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	orderResponse, err := s.toOrderResponse(tx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.OrderResponse]{
+		Data: &orderResponse,
+	}, nil
+}
+
+func canReadAllOrders(ctx context.Context) bool {
+	return middleware.HasPermission(ctx, middleware.PermissionOrdersReadAll)
+}
+
+// canAccessOrder reports whether the caller owns the order or may read every order;
+// callers hide other orders behind ErrNotFound instead of revealing that they exist
+func canAccessOrder(ctx context.Context, order *entity.Order) bool {
+	return canReadAllOrders(ctx) || order.UserID == middleware.GetUserIDFromContext(ctx)
+}
+
+// placeOrder creates a pending order for the user inside the given transaction,
+// shipping to the given address or to the default address when addressID is empty
+func (s *OrderService) placeOrder(tx *sqlx.Tx, userID, addressID string, items []model.OrderItemRequest) (*entity.Order, error) {
+	if s.RequireVerifiedEmail {
+		user, err := s.UserRepository.GetByID(tx, userID)
+		if err != nil {
+			s.Log.Errorf("error getting user: %v", err)
+			return nil, err
+		}
+		if user.EmailVerifiedAt == nil {
+			return nil, e.ErrEmailNotVerified
+		}
+	}
+
+	var address *entity.Address
+	var err error
+	if addressID != "" {
+		address, err = s.AddressRepository.GetByIDAndUserID(tx, addressID, userID)
+	} else {
+		address, err = s.AddressRepository.GetDefaultByUserID(tx, userID)
+	}
+	if err != nil {
+		s.Log.Errorf("error getting user address: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &entity.Order{
+		ID:                  uuid.NewString(),
+		UserID:              userID,
+		AddressID:           &address.ID,
+		ShippingAddressLine: address.AddressLine,
+		ShippingCity:        address.City,
+		ShippingState:       address.State,
+		ShippingPostalCode:  address.PostalCode,
+		ShippingCountry:     address.Country,
+		Status:              OrderStatusPending,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	if err := s.reserveItems(tx, order, items); err != nil {
+		return nil, err
+	}
+
+	if err := s.OrderRepository.Create(tx, order); err != nil {
+		s.Log.Errorf("error creating order: %v", err)
+		return nil, err
+	}
+
+	for i := range order.Items {
+		if err := s.OrderItemRepository.Create(tx, &order.Items[i]); err != nil {
+			s.Log.Errorf("error creating order item: %v", err)
+			return nil, err
+		}
+
+		for j := range order.Items[i].Options {
+			if err := s.OrderItemRepository.CreateOption(tx, &order.Items[i].Options[j]); err != nil {
+				s.Log.Errorf("error creating order item option: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	return order, nil
+}
+
+// orderLine is a requested line after duplicates were merged
+type orderLine struct {
+	productID string
+	variantID string
+	optionIDs []string
+	quantity  int
+}
+
+// reserveItems prices every requested line, takes it out of stock and attaches the lines to the order
+func (s *OrderService) reserveItems(tx *sqlx.Tx, order *entity.Order, requests []model.OrderItemRequest) error {
+	// Merge duplicate lines so every product, variant and add-on combination is priced once
+	lines := make([]*orderLine, 0, len(requests))
+	linesByKey := make(map[string]*orderLine, len(requests))
+	optionIDs := make([]string, 0)
+	for _, item := range requests {
+		options := slices.Clone(item.OptionIDs)
+		slices.Sort(options)
+		if len(slices.Compact(slices.Clone(options))) != len(options) {
+			s.Log.Errorf("option selected twice for product %s", item.ProductID)
+			return e.ErrInvalidOptions
+		}
+
+		key := item.ProductID + "|" + item.VariantID + "|" + strings.Join(options, ",")
+		if line, ok := linesByKey[key]; ok {
+			line.quantity += item.Quantity
+			continue
+		}
+
+		line := &orderLine{productID: item.ProductID, variantID: item.VariantID, optionIDs: options, quantity: item.Quantity}
+		lines = append(lines, line)
+		linesByKey[key] = line
+		optionIDs = append(optionIDs, options...)
+	}
+
+	// Products without variants hold their own stock, otherwise every variant does
+	productQuantities := make(map[string]int)
+	variantQuantities := make(map[string]int)
+	productIDs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if _, ok := productQuantities[line.productID]; !ok {
+			productIDs = append(productIDs, line.productID)
+			productQuantities[line.productID] = 0
+		}
+		if line.variantID != "" {
+			variantQuantities[line.variantID] += line.quantity
+		} else {
+			productQuantities[line.productID] += line.quantity
+		}
+	}
+
+	// Lock products in a stable order so concurrent orders cannot deadlock
+	slices.Sort(productIDs)
+
+	products := make(map[string]*entity.Product, len(productIDs))
+	for _, productID := range productIDs {
+		product, err := s.ProductRepository.GetByIDForUpdate(tx, productID)
+		if err != nil {
+			s.Log.Errorf("error getting product %s: %v", productID, err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return e.ErrNotFound
+			}
+			return err
+		}
+		if !product.Available() {
+			s.Log.Errorf("product %s is no longer available", productID)
+			return e.ErrProductUnavailable
+		}
+
+		if quantity := productQuantities[productID]; quantity > 0 {
+			variantCount, err := s.VariantRepository.CountByProductID(tx, productID)
+			if err != nil {
+				s.Log.Errorf("error counting variants: %v", err)
+				return err
+			}
+			if variantCount > 0 {
+				s.Log.Errorf("product %s is only sold by variant", productID)
+				return e.ErrVariantRequired
+			}
+
+			// The guarded update only succeeds while enough stock is left
+			affected, err := s.ProductRepository.DecrementStock(tx, productID, quantity)
+			if err != nil {
+				s.Log.Errorf("error decrementing stock: %v", err)
+				return err
+			}
+			if affected == 0 {
+				s.Log.Errorf("insufficient stock for product %s", productID)
+				return e.ErrInsufficientStock
+			}
+		}
+
+		products[productID] = product
+	}
+
+	variants := make(map[string]*entity.ProductVariant, len(variantQuantities))
+	for _, line := range lines {
+		if line.variantID == "" || variants[line.variantID] != nil {
+			continue
+		}
+
+		variant, err := s.VariantRepository.GetByID(tx, line.variantID)
+		if err != nil {
+			s.Log.Errorf("error getting variant %s: %v", line.variantID, err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return e.ErrVariantNotFound
+			}
+			return err
+		}
+		if variant.ProductID != line.productID {
+			s.Log.Errorf("variant %s does not belong to product %s", variant.ID, line.productID)
+			return e.ErrVariantNotFound
+		}
+
+		variants[variant.ID] = variant
+	}
+
+	variantIDs := make([]string, 0, len(variants))
+	for variantID := range variants {
+		variantIDs = append(variantIDs, variantID)
+	}
+	slices.Sort(variantIDs)
+
+	for _, variantID := range variantIDs {
+		affected, err := s.VariantRepository.DecrementStock(tx, variantID, variantQuantities[variantID])
+		if err != nil {
+			s.Log.Errorf("error decrementing variant stock: %v", err)
+			return err
+		}
+		if affected == 0 {
+			s.Log.Errorf("insufficient stock for variant %s", variantID)
+			return e.ErrInsufficientStock
+		}
+	}
+
+	selections, err := s.OptionRepository.GetSelections(tx, optionIDs)
+	if err != nil {
+		s.Log.Errorf("error getting options: %v", err)
+		return err
+	}
+
+	selectionsByID := make(map[string]entity.ProductOptionSelection, len(selections))
+	for _, selection := range selections {
+		selectionsByID[selection.ID] = selection
+	}
+
+	order.Items = make([]entity.OrderItem, 0, len(lines))
+	order.TotalPrice = 0
+	for _, line := range lines {
+		product := products[line.productID]
+		item := entity.OrderItem{
+			ID:          uuid.NewString(),
+			OrderID:     order.ID,
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Quantity:    line.quantity,
+			UnitPrice:   product.Price,
+			CreatedAt:   order.CreatedAt,
+			UpdatedAt:   order.UpdatedAt,
+		}
+
+		if variant := variants[line.variantID]; variant != nil {
+			item.VariantID = &variant.ID
+			item.VariantName = variant.Name
+			item.UnitPrice += variant.PriceDelta
+		}
+
+		item.Options, err = s.selectOptions(tx, &item, line.optionIDs, selectionsByID)
+		if err != nil {
+			return err
+		}
+		for _, option := range item.Options {
+			item.UnitPrice += option.Price
+		}
+
+		item.Subtotal = item.UnitPrice * float64(item.Quantity)
+		order.Items = append(order.Items, item)
+		order.TotalPrice += item.Subtotal
+	}
+
+	return nil
+}
+
+// priceLine prices a product with a variant and add-ons the way checkout does, without reserving stock,
+// so a cart can check and show a line before it is ordered. The variant is returned for its stock.
+func (s *OrderService) priceLine(tx *sqlx.Tx, product *entity.Product, variantID string, optionIDs []string) (*entity.OrderItem, *entity.ProductVariant, error) {
+	options := slices.Clone(optionIDs)
+	slices.Sort(options)
+	if len(slices.Compact(slices.Clone(options))) != len(options) {
+		s.Log.Errorf("option selected twice for product %s", product.ID)
+		return nil, nil, e.ErrInvalidOptions
+	}
+
+	item := &entity.OrderItem{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		UnitPrice:   product.Price,
+	}
+
+	variantCount, err := s.VariantRepository.CountByProductID(tx, product.ID)
+	if err != nil {
+		s.Log.Errorf("error counting variants: %v", err)
+		return nil, nil, err
+	}
+
+	var variant *entity.ProductVariant
+	switch {
+	case variantID == "" && variantCount > 0:
+		return nil, nil, e.ErrVariantRequired
+	case variantID != "":
+		variant, err = s.VariantRepository.GetByID(tx, variantID)
+		if err != nil {
+			s.Log.Errorf("error getting variant %s: %v", variantID, err)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, nil, e.ErrVariantNotFound
+			}
+			return nil, nil, err
+		}
+		if variant.ProductID != product.ID {
+			s.Log.Errorf("variant %s does not belong to product %s", variant.ID, product.ID)
+			return nil, nil, e.ErrVariantNotFound
+		}
+
+		item.VariantID = &variant.ID
+		item.VariantName = variant.Name
+		item.UnitPrice += variant.PriceDelta
+	}
+
+	selections, err := s.OptionRepository.GetSelections(tx, options)
+	if err != nil {
+		s.Log.Errorf("error getting options: %v", err)
+		return nil, nil, err
+	}
+
+	selectionsByID := make(map[string]entity.ProductOptionSelection, len(selections))
+	for _, selection := range selections {
+		selectionsByID[selection.ID] = selection
+	}
+
+	item.Options, err = s.selectOptions(tx, item, options, selectionsByID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, option := range item.Options {
+		item.UnitPrice += option.Price
+	}
+
+	return item, variant, nil
+}
+
+// selectOptions checks the chosen add-ons against the option groups of the product
+// and snapshots them for the order line
+func (s *OrderService) selectOptions(
+	tx *sqlx.Tx,
+	item *entity.OrderItem,
+	optionIDs []string,
+	selections map[string]entity.ProductOptionSelection,
+) ([]entity.OrderItemOption, error) {
+	groups, err := s.OptionRepository.GetGroupsByProductID(tx, item.ProductID)
+	if err != nil {
+		s.Log.Errorf("error getting option groups: %v", err)
+		return nil, err
+	}
+
+	selected := make(map[string]int, len(groups))
+	options := make([]entity.OrderItemOption, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		selection, ok := selections[optionID]
+		if !ok || selection.ProductID != item.ProductID {
+			s.Log.Errorf("option %s is not available for product %s", optionID, item.ProductID)
+			return nil, e.ErrInvalidOptions
+		}
+
+		selected[selection.GroupID]++
+		options = append(options, entity.OrderItemOption{
+			ID:          uuid.NewString(),
+			OrderItemID: item.ID,
+			OptionID:    &selection.ID,
+			GroupName:   selection.GroupName,
+			OptionName:  selection.Name,
+			Price:       selection.Price,
+		})
+	}
+
+	for _, group := range groups {
+		count := selected[group.ID]
+		if group.Required && count == 0 || group.MaxSelect > 0 && count > group.MaxSelect {
+			s.Log.Errorf("selection for option group %s of product %s is invalid", group.ID, item.ProductID)
+			return nil, e.ErrInvalidOptions
+		}
+	}
+
+	return options, nil
+}
+
+// transition validates and applies a status change, returning stock to the products on cancellation
+// and refunding the payment when a paid order is cancelled or refunded
+func (s *OrderService) transition(ctx context.Context, tx *sqlx.Tx, order *entity.Order, status string) error {
+	if err := ValidateOrderTransition(order.Status, status); err != nil {
+		s.Log.Errorf("error transitioning order %s: %v", order.ID, err)
+		return err
+	}
+
+	if status == OrderStatusCancelled {
+		if err := s.loadItems(tx, order); err != nil {
+			return err
+		}
+
+		for _, item := range order.Items {
+			var err error
+			switch {
+			case item.VariantID != nil:
+				err = s.VariantRepository.IncrementStock(tx, *item.VariantID, item.Quantity)
+			case item.VariantName != "":
+				// The variant was removed from the catalog, there is nothing left to restock
+				continue
+			default:
+				err = s.ProductRepository.IncrementStock(tx, item.ProductID, item.Quantity)
+			}
+			if err != nil {
+				s.Log.Errorf("error returning stock for order %s: %v", order.ID, err)
+				return err
+			}
+		}
+	}
+
+	// Anything past PENDING was paid for, so the customer gets the money back
+	if (status == OrderStatusCancelled || status == OrderStatusRefunded) && order.Status != OrderStatusPending {
+		if err := s.refundPayments(ctx, tx, order); err != nil {
+			return err
+		}
+	}
+
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	if err := s.OrderRepository.UpdateStatus(tx, order); err != nil {
+		s.Log.Errorf("error updating order status: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// refundPayments refunds every captured payment of the order at the gateway. A payment the gateway
+// already refunded, e.g. through a payment.refunded webhook, is no longer SUCCEEDED and is skipped
+func (s *OrderService) refundPayments(ctx context.Context, tx *sqlx.Tx, order *entity.Order) error {
+	payments, err := s.PaymentRepository.GetByOrderIDForUpdate(tx, order.ID)
+	if err != nil {
+		s.Log.Errorf("error getting payments for order %s: %v", order.ID, err)
+		return err
+	}
+
+	paid := false
+	for i := range payments {
+		p := &payments[i]
+		if p.Status == PaymentStatusRefunded {
+			paid = true
+		}
+		if p.Status != PaymentStatusSucceeded {
+			continue
+		}
+		paid = true
+		if p.Gateway != s.Gateway.Name() {
+			s.Log.Warnf("payment %s of order %s went through gateway %s, refund it there manually", p.ID, order.ID, p.Gateway)
+			continue
+		}
+
+		if _, err := s.Gateway.Refund(ctx, p.IntentID, p.Amount); err != nil {
+			s.Log.Errorf("error refunding payment %s: %v", p.ID, err)
+			return err
+		}
+
+		p.Status = PaymentStatusRefunded
+		p.UpdatedAt = time.Now()
+		if err := s.PaymentRepository.UpdateStatus(tx, p); err != nil {
+			s.Log.Errorf("error updating payment status: %v", err)
+			return err
+		}
+	}
+
+	if !paid {
+		s.Log.Warnf("order %s is %s without a captured payment to refund", order.ID, order.Status)
+	}
+
+	return nil
+}
+
+// loadItems fetches the lines of an order unless they are already attached
+func (s *OrderService) loadItems(tx *sqlx.Tx, order *entity.Order) error {
+	if order.Items != nil {
+		return nil
+	}
+
+	items, err := s.OrderItemRepository.GetByOrderID(tx, order.ID)
+	if err != nil {
+		s.Log.Errorf("error getting items for order %s: %v", order.ID, err)
+		return err
+	}
+
+	if err = s.attachOptions(tx, items); err != nil {
+		return err
+	}
+
+	order.Items = items
+	return nil
+}
+
+// attachOptions loads the add-ons of the given lines in one query
+func (s *OrderService) attachOptions(tx *sqlx.Tx, items []entity.OrderItem) error {
+	itemIDs := make([]string, len(items))
+	for i, item := range items {
+		itemIDs[i] = item.ID
+	}
+
+	options, err := s.OrderItemRepository.GetOptionsByItemIDs(tx, itemIDs)
+	if err != nil {
+		s.Log.Errorf("error getting order item options: %v", err)
+		return err
+	}
+
+	optionsByItem := make(map[string][]entity.OrderItemOption, len(items))
+	for _, option := range options {
+		optionsByItem[option.OrderItemID] = append(optionsByItem[option.OrderItemID], option)
+	}
+
+	for i := range items {
+		items[i].Options = optionsByItem[items[i].ID]
+	}
+
+	return nil
+}
+
+// toOrderResponse maps an order to a response from the snapshots taken when it was placed
+func (s *OrderService) toOrderResponse(tx *sqlx.Tx, order *entity.Order) (*model.OrderResponse, error) {
+	if err := s.loadItems(tx, order); err != nil {
+		return nil, err
+	}
+
+	itemResponses := make([]model.OrderItemResponse, len(order.Items))
+	for i, item := range order.Items {
+		itemResponses[i] = model.OrderItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			VariantID:   item.VariantID,
+			VariantName: item.VariantName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Subtotal:    item.Subtotal,
+		}
+
+		for _, option := range item.Options {
+			itemResponses[i].Options = append(itemResponses[i].Options, model.OrderItemOptionResponse{
+				GroupName:  option.GroupName,
+				OptionName: option.OptionName,
+				Price:      option.Price,
+			})
+		}
+	}
+
+	return &model.OrderResponse{
+		ID:         order.ID,
+		UserID:     order.UserID,
+		AddressID:  order.AddressID,
+		Items:      itemResponses,
+		TotalPrice: order.TotalPrice,
+		Status:     order.Status,
+		CreatedAt:  helper.FormatTime(order.CreatedAt),
+		UpdatedAt:  helper.FormatTime(order.UpdatedAt),
+		ShippingAddress: model.ShippingAddressResponse{
+			AddressLine: order.ShippingAddressLine,
+			City:        order.ShippingCity,
+			State:       order.ShippingState,
+			PostalCode:  order.ShippingPostalCode,
+			Country:     order.ShippingCountry,
+		},
+	}, nil
+}
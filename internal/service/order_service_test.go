@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/savioruz/bake/pkg/payment"
+	"github.com/sirupsen/logrus"
+)
+
+// testDB connects to the migrated database in TEST_DATABASE_DSN and skips the test without one,
+// e.g. TEST_DATABASE_DSN="root:secret@tcp(localhost:3306)/bake_test?parseTime=True&loc=Local"
+func testDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set, skipping database test")
+	}
+
+	db, err := sqlx.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("connecting to database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func testLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+// seedOrderFixtures creates a user with a default address and a product with the given stock,
+// removing them again with their orders when the test ends
+func seedOrderFixtures(t *testing.T, db *sqlx.DB, stock int) (userID, productID string) {
+	t.Helper()
+
+	now := time.Now()
+	user := &entity.User{
+		ID:        uuid.NewString(),
+		Email:     uuid.NewString() + "@example.com",
+		Password:  "not-a-real-hash",
+		Name:      "Stock Test",
+		Role:      "user",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	address := &entity.Address{
+		ID:          uuid.NewString(),
+		UserID:      user.ID,
+		AddressLine: "1 Test Street",
+		City:        "Test City",
+		State:       "Test State",
+		PostalCode:  "12345",
+		Country:     "Test Country",
+		IsDefault:   true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	product := &entity.Product{
+		ID:          uuid.NewString(),
+		Name:        "Stock Test Cake",
+		Description: "A cake that sells out",
+		Price:       10,
+		Stock:       stock,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	tx := db.MustBegin()
+	if err := repository.NewUserRepository(db).Create(tx, user); err != nil {
+		tx.Rollback()
+		t.Fatalf("creating user: %v", err)
+	}
+	if err := repository.NewAddressRepository(db).Create(tx, address); err != nil {
+		tx.Rollback()
+		t.Fatalf("creating address: %v", err)
+	}
+	if err := repository.NewProductRepository(db).Create(tx, product); err != nil {
+		tx.Rollback()
+		t.Fatalf("creating product: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("committing fixtures: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.MustExec(`DELETE FROM orders WHERE user_id = ?`, user.ID)
+		db.MustExec(`DELETE FROM products WHERE id = ?`, product.ID)
+		db.MustExec(`DELETE FROM addresses WHERE id = ?`, address.ID)
+		db.MustExec(`DELETE FROM users WHERE id = ?`, user.ID)
+	})
+
+	return user.ID, product.ID
+}
+
+func newTestOrderService(db *sqlx.DB) *OrderService {
+	return NewOrderService(
+		repository.NewOrderRepository(db),
+		repository.NewOrderItemRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewVariantRepository(db),
+		repository.NewOptionRepository(db),
+		repository.NewAddressRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewPaymentRepository(db),
+		payment.NewSandboxGateway(&payment.Config{WebhookSecret: "test", Currency: "IDR"}),
+		db,
+		testLogger(),
+		validator.New(),
+		false,
+	)
+}
+
+func TestOrderServiceCreateConcurrentStock(t *testing.T) {
+	const (
+		stock  = 3
+		orders = 10
+	)
+
+	db := testDB(t)
+	userID, productID := seedOrderFixtures(t, db, stock)
+	service := newTestOrderService(db)
+	ctx := context.WithValue(context.Background(), middleware.UserIDKey, userID)
+
+	var wg sync.WaitGroup
+	errs := make([]error, orders)
+	start := make(chan struct{})
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = service.Create(ctx, &model.CreateOrderRequest{
+				Items: []model.OrderItemRequest{{ProductID: productID, Quantity: 1}},
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, e.ErrInsufficientStock):
+		default:
+			t.Errorf("order %d: unexpected error %v", i, err)
+		}
+	}
+	if succeeded != stock {
+		t.Errorf("succeeded orders = %d, want %d", succeeded, stock)
+	}
+
+	var remaining int
+	if err := db.Get(&remaining, `SELECT stock FROM products WHERE id = ?`, productID); err != nil {
+		t.Fatalf("reading stock: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("remaining stock = %d, want 0", remaining)
+	}
+}
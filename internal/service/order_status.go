@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+
+	e "github.com/savioruz/bake/pkg/error"
+)
+
+const (
+	OrderStatusPending   = "PENDING"
+	OrderStatusPaid      = "PAID"
+	OrderStatusBaking    = "BAKING"
+	OrderStatusReady     = "READY"
+	OrderStatusShipped   = "SHIPPED"
+	OrderStatusDelivered = "DELIVERED"
+	OrderStatusCancelled = "CANCELLED"
+	OrderStatusRefunded  = "REFUNDED"
+)
+
+// orderTransitions lists the statuses an order may move to from each status
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusBaking, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusBaking:    {OrderStatusReady},
+	OrderStatusReady:     {OrderStatusShipped},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+}
+
+// OrderTransitionError is returned when an order cannot move between two statuses
+type OrderTransitionError struct {
+	From string
+	To   string
+}
+
+func (err *OrderTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s to %s", e.ErrInvalidStatusTransition, err.From, err.To)
+}
+
+func (err *OrderTransitionError) Unwrap() error {
+	return e.ErrInvalidStatusTransition
+}
+
+// ValidateOrderTransition checks that an order in status from may move to status to
+func ValidateOrderTransition(from, to string) error {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+
+	return &OrderTransitionError{From: from, To: to}
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/savioruz/bake/pkg/payment"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	PaymentStatusPending   = "PENDING"
+	PaymentStatusSucceeded = "SUCCEEDED"
+	PaymentStatusFailed    = "FAILED"
+	PaymentStatusRefunded  = "REFUNDED"
+)
+
+// paymentTransitions lists the statuses a payment may move to from each status, a failed attempt
+// can still be paid on retry but settled money only ever goes back through a refund
+var paymentTransitions = map[string][]string{
+	PaymentStatusPending:   {PaymentStatusSucceeded, PaymentStatusFailed},
+	PaymentStatusFailed:    {PaymentStatusSucceeded},
+	PaymentStatusSucceeded: {PaymentStatusRefunded},
+	PaymentStatusRefunded:  {},
+}
+
+type PaymentService struct {
+	PaymentRepository *repository.PaymentRepository
+	OrderService      *OrderService
+	Gateway           payment.PaymentGateway
+	DB                *sqlx.DB
+	Log               *logrus.Logger
+	Validate          *validator.Validate
+}
+
+func NewPaymentService(
+	paymentRepo *repository.PaymentRepository,
+	orderService *OrderService,
+	gateway payment.PaymentGateway,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *PaymentService {
+	return &PaymentService{
+		PaymentRepository: paymentRepo,
+		OrderService:      orderService,
+		Gateway:           gateway,
+		DB:                db,
+		Log:               log,
+		Validate:          validate,
+	}
+}
+
+// Create opens a payment intent at the gateway for a pending order of the current user
+func (s *PaymentService) Create(ctx context.Context, request *model.CreatePaymentRequest) (*model.SuccessResponse[*model.PaymentResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	if middleware.GetUserIDFromContext(ctx) == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	order, err := s.OrderService.OrderRepository.GetByIDForUpdate(tx, request.OrderID)
+	if err != nil {
+		s.Log.Errorf("error getting order: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if !canAccessOrder(ctx, order) {
+		err = e.ErrNotFound
+		return nil, err
+	}
+	if order.Status != OrderStatusPending {
+		err = e.ErrOrderNotPayable
+		return nil, err
+	}
+
+	intent, err := s.Gateway.CreateIntent(ctx, order.ID, order.TotalPrice)
+	if err != nil {
+		s.Log.Errorf("error creating payment intent: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	p := &entity.Payment{
+		ID:        uuid.NewString(),
+		OrderID:   order.ID,
+		Gateway:   s.Gateway.Name(),
+		IntentID:  intent.ID,
+		Amount:    intent.Amount,
+		Currency:  intent.Currency,
+		Status:    PaymentStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = s.PaymentRepository.Create(tx, p); err != nil {
+		s.Log.Errorf("error creating payment: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	paymentResponse := toPaymentResponse(p)
+	paymentResponse.ClientSecret = intent.ClientSecret
+	return &model.SuccessResponse[*model.PaymentResponse]{
+		Data: &paymentResponse,
+	}, nil
+}
+
+// HandleWebhook verifies a gateway event and applies it once, acknowledging redelivered events without side effects
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) (*model.SuccessResponse[*model.PaymentWebhookResponse], error) {
+	event, err := s.Gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		s.Log.Errorf("error verifying webhook: %v", err)
+		return nil, e.ErrInvalidSignature
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	inserted, err := s.PaymentRepository.CreateEvent(tx, &entity.PaymentEvent{
+		ID:        event.ID,
+		Gateway:   s.Gateway.Name(),
+		Type:      event.Type,
+		IntentID:  event.IntentID,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("error recording payment event: %v", err)
+		return nil, err
+	}
+
+	webhookResponse := &model.PaymentWebhookResponse{
+		EventID:   event.ID,
+		Duplicate: inserted == 0,
+	}
+
+	if !webhookResponse.Duplicate {
+		if err = s.applyEvent(ctx, tx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.PaymentWebhookResponse]{
+		Data: &webhookResponse,
+	}, nil
+}
+
+// Simulate completes or fails a payment of the current user through the sandbox gateway,
+// feeding its signed webhook through HandleWebhook like a real gateway callback
+func (s *PaymentService) Simulate(ctx context.Context, request *model.SimulatePaymentRequest) (*model.SuccessResponse[*model.PaymentWebhookResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	if middleware.GetUserIDFromContext(ctx) == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	simulator, ok := s.Gateway.(payment.Simulator)
+	if !ok {
+		return nil, e.ErrSimulationUnavailable
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	p, err := s.PaymentRepository.GetByID(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting payment: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	order, err := s.OrderService.OrderRepository.GetByID(tx, p.OrderID)
+	if err != nil {
+		s.Log.Errorf("error getting order: %v", err)
+		return nil, err
+	}
+	if !canAccessOrder(ctx, order) || p.Gateway != s.Gateway.Name() {
+		err = e.ErrNotFound
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	eventType := payment.EventPaymentSucceeded
+	if request.Outcome == "failed" {
+		eventType = payment.EventPaymentFailed
+	}
+
+	payload, signature, err := simulator.Simulate(ctx, p.IntentID, eventType)
+	if err != nil {
+		s.Log.Errorf("error simulating payment: %v", err)
+		return nil, err
+	}
+
+	return s.HandleWebhook(ctx, payload, signature)
+}
+
+// applyEvent updates the payment and moves its order along the lifecycle
+func (s *PaymentService) applyEvent(ctx context.Context, tx *sqlx.Tx, event *payment.Event) error {
+	var paymentStatus, orderStatus string
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		paymentStatus, orderStatus = PaymentStatusSucceeded, OrderStatusPaid
+	case payment.EventPaymentFailed:
+		paymentStatus = PaymentStatusFailed
+	case payment.EventPaymentRefunded:
+		paymentStatus, orderStatus = PaymentStatusRefunded, OrderStatusRefunded
+	default:
+		s.Log.Infof("ignoring payment event %s of type %s", event.ID, event.Type)
+		return nil
+	}
+
+	p, err := s.PaymentRepository.GetByIntentIDForUpdate(tx, s.Gateway.Name(), event.IntentID)
+	if err != nil {
+		s.Log.Errorf("error getting payment for intent %s: %v", event.IntentID, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return e.ErrNotFound
+		}
+		return err
+	}
+
+	// Late, replayed or out of order events must not undo a settled payment, e.g. a failed attempt
+	// reported after the payment succeeded would otherwise skip the refund on cancellation
+	if !slices.Contains(paymentTransitions[p.Status], paymentStatus) {
+		s.Log.Warnf("ignoring payment event %s of type %s, payment %s is already %s", event.ID, event.Type, p.ID, p.Status)
+		return nil
+	}
+
+	p.Status = paymentStatus
+	p.UpdatedAt = time.Now()
+	if err := s.PaymentRepository.UpdateStatus(tx, p); err != nil {
+		s.Log.Errorf("error updating payment status: %v", err)
+		return err
+	}
+
+	if orderStatus == "" {
+		return nil
+	}
+
+	order, err := s.OrderService.OrderRepository.GetByIDForUpdate(tx, p.OrderID)
+	if err != nil {
+		s.Log.Errorf("error getting order: %v", err)
+		return err
+	}
+	if order.Status == orderStatus {
+		return nil
+	}
+
+	// The money has moved either way, so an order that can no longer follow is logged for manual follow-up
+	if err := s.OrderService.transition(ctx, tx, order, orderStatus); err != nil {
+		if !errors.Is(err, e.ErrInvalidStatusTransition) {
+			return err
+		}
+		// A payment that arrives after the order was cancelled goes straight back to the customer
+		if orderStatus == OrderStatusPaid && order.Status == OrderStatusCancelled {
+			return s.OrderService.refundPayments(ctx, tx, order)
+		}
+		s.Log.Warnf("payment %s is %s but order %s is %s", p.ID, p.Status, order.ID, order.Status)
+		return nil
+	}
+
+	return nil
+}
+
+func toPaymentResponse(p *entity.Payment) *model.PaymentResponse {
+	return &model.PaymentResponse{
+		ID:        p.ID,
+		OrderID:   p.OrderID,
+		Gateway:   p.Gateway,
+		IntentID:  p.IntentID,
+		Amount:    p.Amount,
+		Currency:  p.Currency,
+		Status:    p.Status,
+		CreatedAt: helper.FormatTime(p.CreatedAt),
+		UpdatedAt: helper.FormatTime(p.UpdatedAt),
+	}
+}
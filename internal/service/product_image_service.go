@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/imaging"
+	"github.com/savioruz/bake/pkg/storage"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxImagesPerProduct caps the gallery of a single product
+	MaxImagesPerProduct = 20
+	thumbnailSize       = 320
+)
+
+type ProductImageService struct {
+	ProductRepository      *repository.ProductRepository
+	ProductImageRepository *repository.ProductImageRepository
+	Store                  storage.BlobStore
+	DB                     *sqlx.DB
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
+	// MaxSize is the largest file in bytes that is accepted
+	MaxSize int64
+}
+
+func NewProductImageService(
+	productRepo *repository.ProductRepository,
+	productImageRepo *repository.ProductImageRepository,
+	store storage.BlobStore,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+	maxSize int64,
+) *ProductImageService {
+	return &ProductImageService{
+		ProductRepository:      productRepo,
+		ProductImageRepository: productImageRepo,
+		Store:                  store,
+		DB:                     db,
+		Log:                    log,
+		Validate:               validate,
+		MaxSize:                maxSize,
+	}
+}
+
+// Upload stores the images with a thumbnail each and appends them to the gallery of the product
+func (s *ProductImageService) Upload(ctx context.Context, request *model.UploadProductImagesRequest) (*model.SuccessResponse[[]model.ProductImageResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	// Headers are checked up front so a bad file fails the upload before anything is stored, the pixels
+	// are only decoded one file at a time below so a request never holds more than one bitmap
+	for _, upload := range request.Images {
+		if int64(len(upload.Data)) > s.MaxSize {
+			s.Log.Errorf("image %q is %d bytes, the limit is %d", upload.Filename, len(upload.Data), s.MaxSize)
+			return nil, e.ErrImageTooLarge
+		}
+
+		if _, err := imaging.Inspect(upload.Data); err != nil {
+			s.Log.Errorf("error inspecting image %q: %v", upload.Filename, err)
+			return nil, imageError(err)
+		}
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+
+	// Files already written are removed again when the upload fails halfway
+	var stored []string
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			deleteBlobs(ctx, s.Store, s.Log, stored...)
+			return
+		}
+	}()
+
+	// Locking the product serializes concurrent uploads so positions stay unique
+	if _, err = s.ProductRepository.GetByIDForUpdate(tx, request.ProductID); err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	existing, err := s.ProductImageRepository.GetByProductID(tx, request.ProductID)
+	if err != nil {
+		s.Log.Errorf("error getting product images: %v", err)
+		return nil, err
+	}
+	if len(existing)+len(request.Images) > MaxImagesPerProduct {
+		err = e.ErrTooManyImages
+		return nil, err
+	}
+
+	position, err := s.ProductImageRepository.NextPosition(tx, request.ProductID)
+	if err != nil {
+		s.Log.Errorf("error getting next image position: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	images := make([]model.ProductImageResponse, len(request.Images))
+	for i, upload := range request.Images {
+		var decoded *imaging.Image
+		decoded, err = imaging.Decode(upload.Data)
+		if err != nil {
+			s.Log.Errorf("error decoding image %q: %v", upload.Filename, err)
+			err = imageError(err)
+			return nil, err
+		}
+
+		id := uuid.NewString()
+		data := &entity.ProductImage{
+			ID:           id,
+			ProductID:    request.ProductID,
+			ObjectKey:    "products/" + request.ProductID + "/" + id + decoded.Extension,
+			ThumbnailKey: "products/" + request.ProductID + "/" + id + "_thumb.jpg",
+			ContentType:  decoded.ContentType,
+			Size:         len(upload.Data),
+			Width:        decoded.Bounds().Dx(),
+			Height:       decoded.Bounds().Dy(),
+			Position:     position + i,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		var thumbnail []byte
+		thumbnail, err = imaging.Thumbnail(decoded, thumbnailSize)
+		if err != nil {
+			s.Log.Errorf("error creating thumbnail: %v", err)
+			return nil, err
+		}
+
+		if err = s.Store.Put(ctx, data.ObjectKey, upload.Data, data.ContentType); err != nil {
+			s.Log.Errorf("error storing image: %v", err)
+			return nil, err
+		}
+		stored = append(stored, data.ObjectKey)
+
+		if err = s.Store.Put(ctx, data.ThumbnailKey, thumbnail, "image/jpeg"); err != nil {
+			s.Log.Errorf("error storing thumbnail: %v", err)
+			return nil, err
+		}
+		stored = append(stored, data.ThumbnailKey)
+
+		if err = s.ProductImageRepository.Create(tx, data); err != nil {
+			s.Log.Errorf("error creating product image: %v", err)
+			return nil, err
+		}
+
+		images[i] = toProductImageResponse(s.Store, data)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[[]model.ProductImageResponse]{
+		Data: &images,
+	}, nil
+}
+
+// Reorder sets the gallery order, the request has to list every image of the product once
+func (s *ProductImageService) Reorder(ctx context.Context, request *model.ReorderProductImagesRequest) (*model.SuccessResponse[[]model.ProductImageResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.ProductRepository.GetByIDForUpdate(tx, request.ProductID); err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	existing, err := s.ProductImageRepository.GetByProductID(tx, request.ProductID)
+	if err != nil {
+		s.Log.Errorf("error getting product images: %v", err)
+		return nil, err
+	}
+
+	byID := make(map[string]*entity.ProductImage, len(existing))
+	for i := range existing {
+		byID[existing[i].ID] = &existing[i]
+	}
+
+	if len(request.ImageIDs) != len(existing) {
+		s.Log.Errorf("reorder lists %d of %d images", len(request.ImageIDs), len(existing))
+		err = e.ErrValidation
+		return nil, err
+	}
+
+	images := make([]model.ProductImageResponse, len(request.ImageIDs))
+	for position, id := range request.ImageIDs {
+		image, ok := byID[id]
+		if !ok {
+			err = e.ErrImageNotFound
+			return nil, err
+		}
+		// Dropping the entry catches an id that is listed twice
+		delete(byID, id)
+
+		if err = s.ProductImageRepository.UpdatePosition(tx, id, position); err != nil {
+			s.Log.Errorf("error updating image position: %v", err)
+			return nil, err
+		}
+
+		image.Position = position
+		images[position] = toProductImageResponse(s.Store, image)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[[]model.ProductImageResponse]{
+		Data: &images,
+	}, nil
+}
+
+// Delete removes an image from the gallery and its files from the store
+func (s *ProductImageService) Delete(ctx context.Context, request *model.GetProductImageRequest) (*model.SuccessResponse[*model.GetProductImageRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	image, err := s.ProductImageRepository.GetByID(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting product image: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrImageNotFound
+		}
+		return nil, err
+	}
+	if image.ProductID != request.ProductID {
+		err = e.ErrImageNotFound
+		return nil, err
+	}
+
+	if err = s.ProductImageRepository.Delete(tx, image.ID); err != nil {
+		s.Log.Errorf("error deleting product image: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	deleteBlobs(ctx, s.Store, s.Log, image.ObjectKey, image.ThumbnailKey)
+
+	return &model.SuccessResponse[*model.GetProductImageRequest]{
+		Data: &request,
+	}, nil
+}
+
+// imageError maps an imaging error to the error the client sees
+func imageError(err error) error {
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return e.ErrImageTooLarge
+	}
+	return e.ErrUnsupportedImage
+}
+
+// deleteBlobs removes files from the store on a best effort basis, a leftover file only costs space
+func deleteBlobs(ctx context.Context, store storage.BlobStore, log *logrus.Logger, keys ...string) {
+	// The database change is final, so finish even when the client went away
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Warnf("error deleting stored file %s: %v", key, err)
+		}
+	}
+}
+
+// imageKeys lists the stored files of the given images
+func imageKeys(images []entity.ProductImage) []string {
+	keys := make([]string, 0, len(images)*2)
+	for _, image := range images {
+		keys = append(keys, image.ObjectKey, image.ThumbnailKey)
+	}
+	return keys
+}
+
+func toProductImageResponse(store storage.BlobStore, image *entity.ProductImage) model.ProductImageResponse {
+	return model.ProductImageResponse{
+		ID:           image.ID,
+		URL:          store.URL(image.ObjectKey),
+		ThumbnailURL: store.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		Position:     image.Position,
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -13,27 +14,41 @@ import (
 	"github.com/savioruz/bake/internal/repository"
 	e "github.com/savioruz/bake/pkg/error"
 	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/storage"
 	"github.com/sirupsen/logrus"
 )
 
 type ProductService struct {
-	ProductRepository *repository.ProductRepository
-	DB                *sqlx.DB
-	Log               *logrus.Logger
-	Validate          *validator.Validate
+	ProductRepository  *repository.ProductRepository
+	CategoryRepository *repository.CategoryRepository
+	TagRepository      *repository.TagRepository
+	// ProductImageRepository and Store provide the uploaded gallery of every product
+	ProductImageRepository *repository.ProductImageRepository
+	Store                  storage.BlobStore
+	DB                     *sqlx.DB
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
 }
 
 func NewProductService(
 	productRepo *repository.ProductRepository,
+	categoryRepo *repository.CategoryRepository,
+	tagRepo *repository.TagRepository,
+	productImageRepo *repository.ProductImageRepository,
+	store storage.BlobStore,
 	db *sqlx.DB,
 	log *logrus.Logger,
 	validate *validator.Validate,
 ) *ProductService {
 	return &ProductService{
-		ProductRepository: productRepo,
-		DB:                db,
-		Log:               log,
-		Validate:          validate,
+		ProductRepository:      productRepo,
+		CategoryRepository:     categoryRepo,
+		TagRepository:          tagRepo,
+		ProductImageRepository: productImageRepo,
+		Store:                  store,
+		DB:                     db,
+		Log:                    log,
+		Validate:               validate,
 	}
 }
 
@@ -56,24 +71,20 @@ func (s *ProductService) GetAll(ctx context.Context, request *model.ProductPagin
 		}
 	}()
 
-	products, total, err := s.ProductRepository.GetAll(tx, request)
+	categoryIDs, err := s.categoryFilter(tx, request.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	products, total, err := s.ProductRepository.GetAll(tx, request, categoryIDs)
 	if err != nil {
 		s.Log.Errorf("error getting all products: %v", err)
 		return nil, err
 	}
 
-	productResponses := make([]*model.ProductResponse, len(products))
-	for i, product := range products {
-		productResponses[i] = &model.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-			Stock:       product.Stock,
-			Image:       product.Image,
-			CreatedAt:   helper.FormatTime(product.CreatedAt),
-			UpdatedAt:   helper.FormatTime(product.UpdatedAt),
-		}
+	productResponses, err := s.toProductResponses(tx, products)
+	if err != nil {
+		return nil, err
 	}
 
 	response := model.SuccessResponse[[]*model.ProductResponse]{
@@ -103,6 +114,14 @@ func (s *ProductService) Search(ctx context.Context, query *model.ProductQuery,
 		s.Log.Errorf("validation error for pagination: %v", err)
 		return nil, e.ErrValidation
 	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		s.Log.Errorf("min_price %v is above max_price %v", *query.MinPrice, *query.MaxPrice)
+		return nil, e.ErrValidation
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && query.CreatedFrom.After(*query.CreatedTo) {
+		s.Log.Errorf("created_from %v is after created_to %v", *query.CreatedFrom, *query.CreatedTo)
+		return nil, e.ErrValidation
+	}
 
 	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
@@ -117,24 +136,24 @@ func (s *ProductService) Search(ctx context.Context, query *model.ProductQuery,
 		}
 	}()
 
-	products, total, err := s.ProductRepository.Search(tx, query, pagination)
+	category := ""
+	if query.Category != nil {
+		category = *query.Category
+	}
+	categoryIDs, err := s.categoryFilter(tx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	products, total, err := s.ProductRepository.Search(tx, query, pagination, categoryIDs)
 	if err != nil {
 		s.Log.Errorf("error searching products: %v", err)
 		return nil, err
 	}
 
-	productResponses := make([]*model.ProductResponse, len(products))
-	for i, product := range products {
-		productResponses[i] = &model.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-			Stock:       product.Stock,
-			Image:       product.Image,
-			CreatedAt:   helper.FormatTime(product.CreatedAt),
-			UpdatedAt:   helper.FormatTime(product.UpdatedAt),
-		}
+	productResponses, err := s.toProductResponses(tx, products)
+	if err != nil {
+		return nil, err
 	}
 
 	response := model.SuccessResponse[[]*model.ProductResponse]{
@@ -175,22 +194,20 @@ func (s *ProductService) GetById(ctx context.Context, request *model.GetProductR
 	}()
 
 	var data *entity.Product
-	data, err = s.ProductRepository.GetByID(tx, request.ID)
+	data, err = s.ProductRepository.GetActiveByID(tx, request.ID)
 	if err != nil {
 		s.Log.Errorf("error getting product by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
 		return nil, err
 	}
 
-	productResponse := &model.ProductResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Description: data.Description,
-		Price:       data.Price,
-		Stock:       data.Stock,
-		Image:       data.Image,
-		CreatedAt:   helper.FormatTime(data.CreatedAt),
-		UpdatedAt:   helper.FormatTime(data.UpdatedAt),
+	productResponses, err := s.toProductResponses(tx, []entity.Product{*data})
+	if err != nil {
+		return nil, err
 	}
+	productResponse := productResponses[0]
 
 	if err = tx.Commit(); err != nil {
 		s.Log.Errorf("error committing transaction: %v", err)
@@ -221,36 +238,48 @@ func (s *ProductService) Create(ctx context.Context, request *model.CreateProduc
 		}
 	}()
 
+	if err = s.checkCategory(tx, request.CategoryID); err != nil {
+		return nil, err
+	}
+	if err = s.checkTags(tx, request.TagIDs); err != nil {
+		return nil, err
+	}
+
 	data := &entity.Product{
 		ID:          uuid.NewString(),
+		CategoryID:  request.CategoryID,
 		Name:        request.Name,
 		Description: request.Description,
 		Price:       request.Price,
 		Stock:       request.Stock,
 		Image:       request.Image,
+		IsActive:    true,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if request.IsActive != nil {
+		data.IsActive = *request.IsActive
+	}
 
 	if err := s.ProductRepository.Create(tx, data); err != nil {
 		s.Log.Errorf("error creating product: %v", err)
 		return nil, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		s.Log.Errorf("error committing transaction: %v", err)
+	if err = s.TagRepository.ReplaceProductTags(tx, data.ID, request.TagIDs); err != nil {
+		s.Log.Errorf("error tagging product: %v", err)
 		return nil, err
 	}
 
-	productResponse := &model.ProductResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Description: data.Description,
-		Price:       data.Price,
-		Stock:       data.Stock,
-		Image:       data.Image,
-		CreatedAt:   helper.FormatTime(data.CreatedAt),
-		UpdatedAt:   helper.FormatTime(data.UpdatedAt),
+	productResponses, err := s.toProductResponses(tx, []entity.Product{*data})
+	if err != nil {
+		return nil, err
+	}
+	productResponse := productResponses[0]
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
 	}
 
 	return &model.SuccessResponse[*model.ProductResponse]{
@@ -281,7 +310,8 @@ func (s *ProductService) Update(ctx context.Context, id *model.DeleteProductRequ
 		}
 	}()
 
-	existingProduct, err := s.ProductRepository.GetByID(tx, id.ID)
+	// The row stays locked until the update so stock taken by a concurrent order is not written back
+	existingProduct, err := s.ProductRepository.GetByIDForUpdate(tx, id.ID)
 	if err != nil {
 		s.Log.Errorf("error getting existing product: %v", err)
 		if err == sql.ErrNoRows {
@@ -292,11 +322,14 @@ func (s *ProductService) Update(ctx context.Context, id *model.DeleteProductRequ
 
 	data := &entity.Product{
 		ID:          id.ID,
+		CategoryID:  existingProduct.CategoryID,
 		Name:        existingProduct.Name,
 		Description: existingProduct.Description,
 		Price:       existingProduct.Price,
 		Stock:       existingProduct.Stock,
 		Image:       existingProduct.Image,
+		IsActive:    existingProduct.IsActive,
+		DeletedAt:   existingProduct.DeletedAt,
 		CreatedAt:   existingProduct.CreatedAt,
 		UpdatedAt:   time.Now(),
 	}
@@ -317,6 +350,23 @@ func (s *ProductService) Update(ctx context.Context, id *model.DeleteProductRequ
 	if request.Image != nil {
 		data.Image = *request.Image
 	}
+	if request.IsActive != nil {
+		data.IsActive = *request.IsActive
+	}
+	if request.CategoryID != nil {
+		data.CategoryID = nil
+		if *request.CategoryID != "" {
+			data.CategoryID = request.CategoryID
+		}
+		if err = s.checkCategory(tx, data.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if request.TagIDs != nil {
+		if err = s.checkTags(tx, *request.TagIDs); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.ProductRepository.Update(tx, data); err != nil {
 		s.Log.Errorf("error updating product: %v", err)
@@ -326,20 +376,22 @@ func (s *ProductService) Update(ctx context.Context, id *model.DeleteProductRequ
 		return nil, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		s.Log.Errorf("error committing transaction: %v", err)
+	if request.TagIDs != nil {
+		if err = s.TagRepository.ReplaceProductTags(tx, data.ID, *request.TagIDs); err != nil {
+			s.Log.Errorf("error tagging product: %v", err)
+			return nil, err
+		}
+	}
+
+	productResponses, err := s.toProductResponses(tx, []entity.Product{*data})
+	if err != nil {
 		return nil, err
 	}
+	productResponse := productResponses[0]
 
-	productResponse := &model.ProductResponse{
-		ID:          data.ID,
-		Name:        data.Name,
-		Description: data.Description,
-		Price:       data.Price,
-		Stock:       data.Stock,
-		Image:       data.Image,
-		CreatedAt:   helper.FormatTime(data.CreatedAt),
-		UpdatedAt:   helper.FormatTime(data.UpdatedAt),
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
 	}
 
 	return &model.SuccessResponse[*model.ProductResponse]{
@@ -347,6 +399,8 @@ func (s *ProductService) Update(ctx context.Context, id *model.DeleteProductRequ
 	}, nil
 }
 
+// Delete hides the product from customers, its orders keep referencing it and Restore brings it back.
+// Its gallery files stay in the store until the product is purged.
 func (s *ProductService) Delete(ctx context.Context, request *model.DeleteProductRequest) (*model.SuccessResponse[*model.DeleteProductRequest], error) {
 	if err := s.Validate.Struct(request); err != nil {
 		s.Log.Errorf("validation error for request: %v", err)
@@ -366,20 +420,363 @@ func (s *ProductService) Delete(ctx context.Context, request *model.DeleteProduc
 		}
 	}()
 
-	if err := s.ProductRepository.Delete(tx, request.ID); err != nil {
+	rows, err := s.ProductRepository.SoftDelete(tx, request.ID)
+	if err != nil {
 		s.Log.Errorf("error deleting product: %v", err)
-		if err == sql.ErrNoRows {
-			return nil, e.ErrNotFound
+		return nil, err
+	}
+	if rows == 0 {
+		err = e.ErrNotFound
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.DeleteProductRequest]{
+		Data: &request,
+	}, nil
+}
+
+// GetArchived lists the deleted and inactive products for the admin
+func (s *ProductService) GetArchived(ctx context.Context, request *model.ProductPagination) (*model.SuccessResponse[[]*model.ProductResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
 		}
+	}()
+
+	products, total, err := s.ProductRepository.GetArchived(tx, request)
+	if err != nil {
+		s.Log.Errorf("error getting archived products: %v", err)
 		return nil, err
 	}
 
+	productResponses, err := s.toProductResponses(tx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	response := model.SuccessResponse[[]*model.ProductResponse]{
+		Data: &productResponses,
+		Paginate: &model.Paginate{
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalPages: helper.CalculateTotalPages(total, request.Limit),
+			TotalItems: total,
+		},
+	}
+
 	if err = tx.Commit(); err != nil {
 		s.Log.Errorf("error committing transaction: %v", err)
 		return nil, err
 	}
 
+	return &response, nil
+}
+
+// Restore undoes Delete, an inactive product stays hidden until it is activated
+func (s *ProductService) Restore(ctx context.Context, request *model.DeleteProductRequest) (*model.SuccessResponse[*model.ProductResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.ProductRepository.GetByIDForUpdate(tx, request.ID); err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if err = s.ProductRepository.Restore(tx, request.ID); err != nil {
+		s.Log.Errorf("error restoring product: %v", err)
+		return nil, err
+	}
+
+	data, err := s.ProductRepository.GetByID(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		return nil, err
+	}
+
+	productResponses, err := s.toProductResponses(tx, []entity.Product{*data})
+	if err != nil {
+		return nil, err
+	}
+	productResponse := productResponses[0]
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.ProductResponse]{
+		Data: &productResponse,
+	}, nil
+}
+
+// Purge removes a deleted product for good with its gallery files. Products that were ever ordered
+// stay deleted, their order items must keep pointing at them, so their files are never cleaned up
+// here and have to be removed through the product image endpoints instead
+func (s *ProductService) Purge(ctx context.Context, request *model.DeleteProductRequest) (*model.SuccessResponse[*model.DeleteProductRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	product, err := s.ProductRepository.GetByIDForUpdate(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrNotFound
+		}
+		return nil, err
+	}
+	if product.DeletedAt == nil {
+		err = e.ErrProductNotDeleted
+		return nil, err
+	}
+
+	hasOrders, err := s.ProductRepository.HasOrders(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error checking product orders: %v", err)
+		return nil, err
+	}
+	if hasOrders {
+		err = e.ErrProductHasOrders
+		return nil, err
+	}
+
+	images, err := s.ProductImageRepository.GetByProductID(tx, request.ID)
+	if err != nil {
+		s.Log.Errorf("error getting product images: %v", err)
+		return nil, err
+	}
+
+	if err = s.ProductRepository.Delete(tx, request.ID); err != nil {
+		s.Log.Errorf("error purging product: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	// The rows are gone with the product, the files only after the delete is committed
+	deleteBlobs(ctx, s.Store, s.Log, imageKeys(images)...)
+
 	return &model.SuccessResponse[*model.DeleteProductRequest]{
 		Data: &request,
 	}, nil
 }
+
+// GetByCategory lists the products of a category and of its subcategories
+func (s *ProductService) GetByCategory(ctx context.Context, request *model.GetCategoryProductsRequest, pagination *model.ProductPagination) (*model.SuccessResponse[[]*model.ProductResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.CategoryRepository.GetBySlug(tx, request.Slug); err != nil {
+		s.Log.Errorf("error getting category by slug: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	pagination.Category = request.Slug
+	return s.GetAll(ctx, pagination)
+}
+
+// categoryFilter resolves a category slug to the ids of the category and its subcategories.
+// No slug means no filter, an unknown slug yields an empty filter that matches no product.
+func (s *ProductService) categoryFilter(tx *sqlx.Tx, slug string) ([]string, error) {
+	if slug == "" {
+		return nil, nil
+	}
+
+	categories, err := s.CategoryRepository.GetAll(tx)
+	if err != nil {
+		s.Log.Errorf("error getting categories: %v", err)
+		return nil, err
+	}
+
+	for _, category := range categories {
+		if category.Slug == slug {
+			return categorySubtree(categories, category.ID), nil
+		}
+	}
+
+	return []string{}, nil
+}
+
+func (s *ProductService) checkCategory(tx *sqlx.Tx, categoryID *string) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	if _, err := s.CategoryRepository.GetByID(tx, *categoryID); err != nil {
+		s.Log.Errorf("error getting category: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return e.ErrCategoryNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProductService) checkTags(tx *sqlx.Tx, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	unique := make(map[string]bool)
+	for _, id := range tagIDs {
+		unique[id] = true
+	}
+
+	count, err := s.TagRepository.CountByIDs(tx, tagIDs)
+	if err != nil {
+		s.Log.Errorf("error counting tags: %v", err)
+		return err
+	}
+	if count != len(unique) {
+		return e.ErrTagNotFound
+	}
+
+	return nil
+}
+
+// toProductResponses maps products to responses and loads their tags in one query
+func (s *ProductService) toProductResponses(tx *sqlx.Tx, products []entity.Product) ([]*model.ProductResponse, error) {
+	ids := make([]string, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+
+	productTags, err := s.TagRepository.GetByProductIDs(tx, ids)
+	if err != nil {
+		s.Log.Errorf("error getting product tags: %v", err)
+		return nil, err
+	}
+
+	tags := make(map[string][]*model.TagResponse)
+	for i := range productTags {
+		tags[productTags[i].ProductID] = append(tags[productTags[i].ProductID], toTagResponse(&productTags[i].Tag))
+	}
+
+	productImages, err := s.ProductImageRepository.GetByProductIDs(tx, ids)
+	if err != nil {
+		s.Log.Errorf("error getting product images: %v", err)
+		return nil, err
+	}
+
+	images := make(map[string][]model.ProductImageResponse)
+	for i := range productImages {
+		images[productImages[i].ProductID] = append(images[productImages[i].ProductID], toProductImageResponse(s.Store, &productImages[i]))
+	}
+
+	productResponses := make([]*model.ProductResponse, len(products))
+	for i, product := range products {
+		productTags := tags[product.ID]
+		if productTags == nil {
+			productTags = []*model.TagResponse{}
+		}
+
+		// The first uploaded image is the cover when no image URL was set
+		productImages := images[product.ID]
+		if productImages == nil {
+			productImages = []model.ProductImageResponse{}
+		}
+		image := product.Image
+		if image == "" && len(productImages) > 0 {
+			image = productImages[0].URL
+		}
+
+		deletedAt := ""
+		if product.DeletedAt != nil {
+			deletedAt = helper.FormatTime(*product.DeletedAt)
+		}
+
+		productResponses[i] = &model.ProductResponse{
+			ID:          product.ID,
+			CategoryID:  product.CategoryID,
+			Name:        product.Name,
+			Description: product.Description,
+			Price:       product.Price,
+			Stock:       product.Stock,
+			Image:       image,
+			Images:      productImages,
+			Tags:        productTags,
+			IsActive:    product.IsActive,
+			DeletedAt:   deletedAt,
+			CreatedAt:   helper.FormatTime(product.CreatedAt),
+			UpdatedAt:   helper.FormatTime(product.UpdatedAt),
+		}
+	}
+
+	return productResponses, nil
+}
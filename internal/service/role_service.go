@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// RoleService manages roles and serves their permissions to the auth middleware from an in-memory cache,
+// the cache is dropped on every change and reloaded after CacheTTL so other instances pick changes up too
+type RoleService struct {
+	RoleRepository *repository.RoleRepository
+	UserRepository *repository.UserRepository
+	DB             *sqlx.DB
+	Log            *logrus.Logger
+	Validate       *validator.Validate
+	CacheTTL       time.Duration
+
+	mu          sync.RWMutex
+	permissions map[string][]string
+	loadedAt    time.Time
+}
+
+func NewRoleService(
+	roleRepo *repository.RoleRepository,
+	userRepo *repository.UserRepository,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+	cacheTTL time.Duration,
+) *RoleService {
+	return &RoleService{
+		RoleRepository: roleRepo,
+		UserRepository: userRepo,
+		DB:             db,
+		Log:            log,
+		Validate:       validate,
+		CacheTTL:       cacheTTL,
+	}
+}
+
+// Permissions implements middleware.PermissionProvider
+func (s *RoleService) Permissions(ctx context.Context, role string) ([]string, error) {
+	s.mu.RLock()
+	if s.permissions != nil && time.Since(s.loadedAt) < s.CacheTTL {
+		permissions := s.permissions[role]
+		s.mu.RUnlock()
+		return permissions, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another request may have reloaded the cache while we waited for the lock
+	if s.permissions == nil || time.Since(s.loadedAt) >= s.CacheTTL {
+		rolePermissions, err := s.RoleRepository.LoadRolePermissions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.permissions = make(map[string][]string)
+		for _, rp := range rolePermissions {
+			s.permissions[rp.RoleName] = append(s.permissions[rp.RoleName], rp.PermissionName)
+		}
+		s.loadedAt = time.Now()
+	}
+
+	return s.permissions[role], nil
+}
+
+func (s *RoleService) invalidate() {
+	s.mu.Lock()
+	s.permissions = nil
+	s.mu.Unlock()
+}
+
+func (s *RoleService) GetAll(ctx context.Context) (*model.SuccessResponse[[]*model.RoleResponse], error) {
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	roles, err := s.RoleRepository.GetAll(tx)
+	if err != nil {
+		s.Log.Errorf("error getting roles: %v", err)
+		return nil, err
+	}
+
+	rolePermissions, err := s.RoleRepository.GetRolePermissions(tx)
+	if err != nil {
+		s.Log.Errorf("error getting role permissions: %v", err)
+		return nil, err
+	}
+
+	grants := make(map[string][]string)
+	for _, rp := range rolePermissions {
+		grants[rp.RoleName] = append(grants[rp.RoleName], rp.PermissionName)
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	roleResponses := make([]*model.RoleResponse, len(roles))
+	for i := range roles {
+		roleResponses[i] = toRoleResponse(&roles[i], grants[roles[i].Name])
+	}
+
+	return &model.SuccessResponse[[]*model.RoleResponse]{
+		Data: &roleResponses,
+	}, nil
+}
+
+func (s *RoleService) GetPermissions(ctx context.Context) (*model.SuccessResponse[[]*model.PermissionResponse], error) {
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	permissions, err := s.RoleRepository.GetAllPermissions(tx)
+	if err != nil {
+		s.Log.Errorf("error getting permissions: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	permissionResponses := make([]*model.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		permissionResponses[i] = &model.PermissionResponse{
+			Name:        permission.Name,
+			Description: permission.Description,
+		}
+	}
+
+	return &model.SuccessResponse[[]*model.PermissionResponse]{
+		Data: &permissionResponses,
+	}, nil
+}
+
+// Save creates a role or replaces its description and permissions, the admin role always keeps every permission
+func (s *RoleService) Save(ctx context.Context, request *model.SaveRoleRequest) (*model.SuccessResponse[*model.RoleResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	if request.Name == RoleAdmin {
+		return nil, e.ErrRoleProtected
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	known, err := s.RoleRepository.GetAllPermissions(tx)
+	if err != nil {
+		s.Log.Errorf("error getting permissions: %v", err)
+		return nil, err
+	}
+
+	for _, permission := range request.Permissions {
+		found := false
+		for _, k := range known {
+			if k.Name == permission {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.Log.Errorf("unknown permission %q", permission)
+			err = e.ErrValidation
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	role := &entity.Role{
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = s.RoleRepository.Save(tx, role); err != nil {
+		s.Log.Errorf("error saving role: %v", err)
+		return nil, err
+	}
+
+	if err = s.RoleRepository.ReplacePermissions(tx, role.Name, request.Permissions); err != nil {
+		s.Log.Errorf("error saving role permissions: %v", err)
+		return nil, err
+	}
+
+	role, err = s.RoleRepository.GetByName(tx, role.Name)
+	if err != nil {
+		s.Log.Errorf("error getting role: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+	s.invalidate()
+
+	roleResponse := toRoleResponse(role, request.Permissions)
+	return &model.SuccessResponse[*model.RoleResponse]{
+		Data: &roleResponse,
+	}, nil
+}
+
+func (s *RoleService) Delete(ctx context.Context, request *model.GetRoleRequest) (*model.SuccessResponse[*model.GetRoleRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	if request.Name == RoleAdmin || request.Name == RoleUser {
+		return nil, e.ErrRoleProtected
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.RoleRepository.GetByName(tx, request.Name); err != nil {
+		s.Log.Errorf("error getting role: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	count, err := s.UserRepository.CountByRole(tx, request.Name)
+	if err != nil {
+		s.Log.Errorf("error counting users of role: %v", err)
+		return nil, err
+	}
+	if count > 0 {
+		err = e.ErrRoleInUse
+		return nil, err
+	}
+
+	if err = s.RoleRepository.Delete(tx, request.Name); err != nil {
+		s.Log.Errorf("error deleting role: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+	s.invalidate()
+
+	return &model.SuccessResponse[*model.GetRoleRequest]{
+		Data: &request,
+	}, nil
+}
+
+func toRoleResponse(role *entity.Role, permissions []string) *model.RoleResponse {
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	return &model.RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   helper.FormatTime(role.CreatedAt),
+		UpdatedAt:   helper.FormatTime(role.UpdatedAt),
+	}
+}
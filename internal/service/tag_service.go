@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type TagService struct {
+	TagRepository *repository.TagRepository
+	DB            *sqlx.DB
+	Log           *logrus.Logger
+	Validate      *validator.Validate
+}
+
+func NewTagService(
+	tagRepo *repository.TagRepository,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *TagService {
+	return &TagService{
+		TagRepository: tagRepo,
+		DB:            db,
+		Log:           log,
+		Validate:      validate,
+	}
+}
+
+func (s *TagService) GetAll(ctx context.Context) (*model.SuccessResponse[[]*model.TagResponse], error) {
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	tags, err := s.TagRepository.GetAll(tx)
+	if err != nil {
+		s.Log.Errorf("error getting tags: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	tagResponses := make([]*model.TagResponse, len(tags))
+	for i := range tags {
+		tagResponses[i] = toTagResponse(&tags[i])
+	}
+
+	return &model.SuccessResponse[[]*model.TagResponse]{
+		Data: &tagResponses,
+	}, nil
+}
+
+// Save creates a tag, or renames the tag with the request id when it is set
+func (s *TagService) Save(ctx context.Context, request *model.SaveTagRequest) (*model.SuccessResponse[*model.TagResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	slug, err := resolveSlug(request.Slug, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	existing, err := s.TagRepository.GetBySlug(tx, slug)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = nil
+	case err != nil:
+		s.Log.Errorf("error getting tag by slug: %v", err)
+		return nil, err
+	case existing.ID != request.ID:
+		err = e.ErrSlugExists
+		return nil, err
+	}
+
+	now := time.Now()
+	var data *entity.Tag
+	if request.ID == "" {
+		data = &entity.Tag{
+			ID:        uuid.NewString(),
+			Name:      request.Name,
+			Slug:      slug,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		err = s.TagRepository.Create(tx, data)
+	} else {
+		data, err = s.TagRepository.GetByID(tx, request.ID)
+		if err != nil {
+			s.Log.Errorf("error getting tag: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = e.ErrTagNotFound
+			}
+			return nil, err
+		}
+
+		data.Name = request.Name
+		data.Slug = slug
+		data.UpdatedAt = now
+		err = s.TagRepository.Update(tx, data)
+	}
+	if err != nil {
+		s.Log.Errorf("error saving tag: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	tagResponse := toTagResponse(data)
+	return &model.SuccessResponse[*model.TagResponse]{
+		Data: &tagResponse,
+	}, nil
+}
+
+// Delete removes a tag and detaches it from every product
+func (s *TagService) Delete(ctx context.Context, request *model.GetTagRequest) (*model.SuccessResponse[*model.GetTagRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.TagRepository.GetByID(tx, request.ID); err != nil {
+		s.Log.Errorf("error getting tag: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	if err = s.TagRepository.Delete(tx, request.ID); err != nil {
+		s.Log.Errorf("error deleting tag: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.GetTagRequest]{
+		Data: &request,
+	}, nil
+}
+
+func toTagResponse(tag *entity.Tag) *model.TagResponse {
+	return &model.TagResponse{
+		ID:   tag.ID,
+		Name: tag.Name,
+		Slug: tag.Slug,
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/savioruz/bake/internal/domain/model"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// deletedUserName replaces the name of an anonymized account
+const deletedUserName = "Deleted user"
+
+func (s *UserService) UpdateProfile(ctx context.Context, request *model.UpdateProfileRequest) (*model.UserResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getUser(tx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		data.Name = *request.Name
+	}
+	if request.Phone != nil {
+		data.Phone = *request.Phone
+	}
+	data.UpdatedAt = time.Now()
+
+	if err = s.UserRepository.UpdateProfile(tx, data); err != nil {
+		s.Log.Errorf("error updating profile: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return toUserResponse(data), nil
+}
+
+// ChangePassword sets a new password after checking the current one and signs the user out everywhere
+func (s *UserService) ChangePassword(ctx context.Context, request *model.ChangePasswordRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getUser(tx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(request.CurrentPassword)) != nil {
+		err = e.ErrInvalidPassword
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		s.Log.Errorf("error hashing password: %v", err)
+		return err
+	}
+
+	now := time.Now()
+	if err = s.UserRepository.UpdatePassword(tx, userID, string(hashedPassword), now); err != nil {
+		s.Log.Errorf("error updating password: %v", err)
+		return err
+	}
+
+	// A pending reset link would otherwise undo the change
+	if err = s.UserTokenRepository.InvalidateByUserID(tx, userID, UserTokenPurposePasswordReset, now); err != nil {
+		s.Log.Errorf("error invalidating reset tokens: %v", err)
+		return err
+	}
+
+	if err = s.RefreshTokenRepository.RevokeByUserID(tx, userID, now); err != nil {
+		s.Log.Errorf("error revoking refresh tokens: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	if err = s.Denylist.RevokeUser(ctx, userID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAccount anonymizes the current user instead of removing them, their orders stay for accounting
+func (s *UserService) DeleteAccount(ctx context.Context, request *model.DeleteAccountRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getUser(tx, userID, true)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(request.Password)) != nil {
+		err = e.ErrInvalidPassword
+		return err
+	}
+
+	now := time.Now()
+	// The id keeps the placeholder unique and frees the original address for a new registration
+	email := "deleted+" + userID + "@deleted.invalid"
+	if err = s.UserRepository.Anonymize(tx, userID, email, deletedUserName, now); err != nil {
+		s.Log.Errorf("error anonymizing user: %v", err)
+		return err
+	}
+
+	if err = s.AddressRepository.DeleteByUserID(tx, userID); err != nil {
+		s.Log.Errorf("error deleting addresses: %v", err)
+		return err
+	}
+
+	// A pending reset link would otherwise hand the account back
+	for _, purpose := range []string{UserTokenPurposePasswordReset, UserTokenPurposeEmailVerification} {
+		if err = s.UserTokenRepository.InvalidateByUserID(tx, userID, purpose, now); err != nil {
+			s.Log.Errorf("error invalidating user tokens: %v", err)
+			return err
+		}
+	}
+
+	if err = s.RecoveryCodeRepository.DeleteByUserID(tx, userID); err != nil {
+		s.Log.Errorf("error deleting recovery codes: %v", err)
+		return err
+	}
+
+	if err = s.RefreshTokenRepository.RevokeByUserID(tx, userID, now); err != nil {
+		s.Log.Errorf("error revoking refresh tokens: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	if err = s.Denylist.RevokeUser(ctx, userID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return err
+	}
+
+	return nil
+}
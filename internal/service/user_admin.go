@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
+)
+
+func (s *UserService) GetAll(ctx context.Context, request *model.UserPagination) (*model.SuccessResponse[[]*model.UserResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	users, total, err := s.UserRepository.GetAll(tx, request)
+	if err != nil {
+		s.Log.Errorf("error getting all users: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	userResponses := make([]*model.UserResponse, len(users))
+	for i := range users {
+		userResponses[i] = toUserResponse(&users[i])
+	}
+
+	return &model.SuccessResponse[[]*model.UserResponse]{
+		Data: &userResponses,
+		Paginate: &model.Paginate{
+			Page:       request.Page,
+			Limit:      request.Limit,
+			TotalPages: helper.CalculateTotalPages(total, request.Limit),
+			TotalItems: total,
+		},
+	}, nil
+}
+
+func (s *UserService) GetByID(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getUser(tx, request.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return toUserResponse(data), nil
+}
+
+// Update changes the role or the disabled flag of a user. Both changes revoke the sessions
+// of the user, tokens carry the role and a disabled user must be signed out right away.
+func (s *UserService) Update(ctx context.Context, request *model.UpdateUserRequest) (*model.UserResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	// Nobody can lock themselves out of the admin endpoints
+	if request.ID == middleware.GetUserIDFromContext(ctx) {
+		return nil, e.ErrOwnAccount
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.getUser(tx, request.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleted accounts are only kept for their orders
+	if data.DeletedAt != nil {
+		err = e.ErrUserNotFound
+		return nil, err
+	}
+
+	now := time.Now()
+	changed := false
+
+	if request.Role != nil && *request.Role != data.Role {
+		if _, err = s.RoleRepository.GetByName(tx, *request.Role); err != nil {
+			s.Log.Errorf("error getting role: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = e.ErrRoleNotFound
+			}
+			return nil, err
+		}
+
+		if err = s.UserRepository.UpdateRole(tx, data.ID, *request.Role, now); err != nil {
+			s.Log.Errorf("error updating role: %v", err)
+			return nil, err
+		}
+		data.Role = *request.Role
+		changed = true
+	}
+
+	if request.Disabled != nil && *request.Disabled != (data.DisabledAt != nil) {
+		var disabledAt *time.Time
+		if *request.Disabled {
+			disabledAt = &now
+		}
+
+		if err = s.UserRepository.SetDisabled(tx, data.ID, disabledAt, now); err != nil {
+			s.Log.Errorf("error updating disabled flag: %v", err)
+			return nil, err
+		}
+		data.DisabledAt = disabledAt
+		changed = true
+	}
+
+	if !changed {
+		if err = tx.Commit(); err != nil {
+			s.Log.Errorf("error committing transaction: %v", err)
+			return nil, err
+		}
+		return toUserResponse(data), nil
+	}
+
+	if err = s.RefreshTokenRepository.RevokeByUserID(tx, data.ID, now); err != nil {
+		s.Log.Errorf("error revoking refresh tokens: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	if err = s.Denylist.RevokeUser(ctx, data.ID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return nil, err
+	}
+
+	data.UpdatedAt = now
+	return toUserResponse(data), nil
+}
+
+// AssignRole changes the role of a user, see Update
+func (s *UserService) AssignRole(ctx context.Context, request *model.AssignRoleRequest) (*model.UserResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	return s.Update(ctx, &model.UpdateUserRequest{
+		ID:   request.UserID,
+		Role: &request.Role,
+	})
+}
+
+// Delete removes a user without orders. Users with orders are refused with ErrUserHasOrders so the order
+// history survives, those are disabled through Update instead
+func (s *UserService) Delete(ctx context.Context, request *model.GetUserRequest) (*model.GetUserRequest, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	if request.ID == middleware.GetUserIDFromContext(ctx) {
+		return nil, e.ErrOwnAccount
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	// The row lock makes an order placed concurrently wait on its foreign key check until the
+	// user is gone, so it fails instead of slipping in between the check and the delete
+	data, err := s.getUser(tx, request.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	hasOrders, err := s.UserRepository.HasOrders(tx, data.ID)
+	if err != nil {
+		s.Log.Errorf("error checking orders of user: %v", err)
+		return nil, err
+	}
+	if hasOrders {
+		err = e.ErrUserHasOrders
+		return nil, err
+	}
+
+	if err = s.UserRepository.Delete(tx, data.ID); err != nil {
+		s.Log.Errorf("error deleting user: %v", err)
+		if helper.IsForeignKeyViolation(err) {
+			err = e.ErrUserHasOrders
+		}
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if err = s.Denylist.RevokeUser(ctx, data.ID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// getUser reads a user by id, optionally holding a row lock, and maps a missing row to ErrUserNotFound
+func (s *UserService) getUser(tx *sqlx.Tx, id string, forUpdate bool) (*entity.User, error) {
+	var data *entity.User
+	var err error
+	if forUpdate {
+		data, err = s.UserRepository.GetByIDForUpdate(tx, id)
+	} else {
+		data, err = s.UserRepository.GetByID(tx, id)
+	}
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
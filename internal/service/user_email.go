@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/mailer"
+	"github.com/savioruz/bake/pkg/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	UserTokenPurposePasswordReset     = "password_reset"
+	UserTokenPurposeEmailVerification = "email_verification"
+)
+
+// backgroundMailTimeout bounds mails sent after the request has been answered
+const backgroundMailTimeout = time.Minute
+
+// ForgotPassword emails a password reset token, unknown emails are ignored so accounts cannot be enumerated
+func (s *UserService) ForgotPassword(ctx context.Context, request *model.ForgotPasswordRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByEmail(tx, request.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.Log.Infof("password reset requested for unknown email")
+			err = tx.Commit()
+			return err
+		}
+		s.Log.Errorf("error getting user by email: %v", err)
+		return err
+	}
+
+	token, err := s.issueUserToken(tx, data.ID, UserTokenPurposePasswordReset, s.Config.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nUse this token to reset your password, it expires in %s:\n\n%s\n",
+		data.Name, s.Config.PasswordResetTTL, token)
+	if s.Config.PasswordResetURL != "" {
+		body += fmt.Sprintf("\nOr open %s?token=%s\n", s.Config.PasswordResetURL, url.QueryEscape(token))
+	}
+	body += "\nIf you did not ask for this, you can ignore this email.\n"
+
+	// The mail is sent in the background so known and unknown emails answer alike and in the same time,
+	// it outlives the request and a delivery failure is only logged
+	message := &mailer.Message{To: data.Email, Subject: "Reset your password", Body: body}
+	go func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, backgroundMailTimeout)
+		defer cancel()
+
+		if err := s.Mailer.Send(ctx, message); err != nil {
+			s.Log.Errorf("error sending password reset email: %v", err)
+		}
+	}(context.WithoutCancel(ctx))
+
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out everywhere
+func (s *UserService) ResetPassword(ctx context.Context, request *model.ResetPasswordRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	token, err := s.consumeUserToken(tx, request.Token, UserTokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+	if err != nil {
+		s.Log.Errorf("error hashing password: %v", err)
+		return err
+	}
+
+	now := time.Now()
+	if err = s.UserRepository.UpdatePassword(tx, token.UserID, string(hashedPassword), now); err != nil {
+		s.Log.Errorf("error updating password: %v", err)
+		return err
+	}
+
+	// Receiving the token proves the user owns the email address
+	if err = s.UserRepository.SetEmailVerified(tx, token.UserID, now); err != nil {
+		s.Log.Errorf("error verifying email: %v", err)
+		return err
+	}
+
+	if err = s.RefreshTokenRepository.RevokeByUserID(tx, token.UserID, now); err != nil {
+		s.Log.Errorf("error revoking refresh tokens: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	if err = s.Denylist.RevokeUser(ctx, token.UserID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *UserService) VerifyEmail(ctx context.Context, request *model.VerifyEmailRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	token, err := s.consumeUserToken(tx, request.Token, UserTokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	if err = s.UserRepository.SetEmailVerified(tx, token.UserID, time.Now()); err != nil {
+		s.Log.Errorf("error verifying email: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResendVerification emails a new verification token to the current user unless the email is verified already
+func (s *UserService) ResendVerification(ctx context.Context) error {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByID(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		return err
+	}
+
+	if data.EmailVerifiedAt != nil {
+		err = tx.Commit()
+		return err
+	}
+
+	token, err := s.issueUserToken(tx, data.ID, UserTokenPurposeEmailVerification, s.Config.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	s.sendVerificationEmail(ctx, data, token)
+	return nil
+}
+
+// issueUserToken replaces any pending token of the purpose with a new one and returns it in plain text
+func (s *UserService) issueUserToken(tx *sqlx.Tx, userID, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	if err := s.UserTokenRepository.InvalidateByUserID(tx, userID, purpose, now); err != nil {
+		s.Log.Errorf("error invalidating user tokens: %v", err)
+		return "", err
+	}
+
+	err := s.UserTokenRepository.Create(tx, &entity.UserToken{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		s.Log.Errorf("error creating user token: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken marks a token used, unknown, used and expired tokens are all rejected alike
+func (s *UserService) consumeUserToken(tx *sqlx.Tx, token, purpose string) (*entity.UserToken, error) {
+	stored, err := s.UserTokenRepository.GetByHashForUpdate(tx, hashUserToken(token), purpose)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrInvalidToken
+		}
+		s.Log.Errorf("error getting user token: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return nil, e.ErrInvalidToken
+	}
+
+	if err := s.UserTokenRepository.MarkUsed(tx, stored.ID, now); err != nil {
+		s.Log.Errorf("error marking user token used: %v", err)
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func (s *UserService) sendVerificationEmail(ctx context.Context, user *entity.User, token string) {
+	link := fmt.Sprintf("%s/api/v1/users/verify-email?token=%s", strings.TrimRight(s.Config.AppURL, "/"), url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening this link, it expires in %s:\n\n%s\n",
+		user.Name, s.Config.EmailVerificationTTL, link)
+
+	if err := s.Mailer.Send(ctx, &mailer.Message{To: user.Email, Subject: "Verify your email address", Body: body}); err != nil {
+		s.Log.Errorf("error sending verification email: %v", err)
+	}
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
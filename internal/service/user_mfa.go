@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/savioruz/bake/pkg/totp"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	// totpSkew accepts the codes of the neighbouring time steps to tolerate clock drift
+	totpSkew = 1
+)
+
+// EnrollTOTP starts two-factor enrollment with a new secret, it is enabled once ConfirmTOTP receives a valid code
+func (s *UserService) EnrollTOTP(ctx context.Context) (*model.TOTPEnrollResponse, error) {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByIDForUpdate(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		return nil, err
+	}
+
+	if data.TOTPEnabledAt != nil {
+		err = e.ErrMFAAlreadyEnabled
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		s.Log.Errorf("error generating totp secret: %v", err)
+		return nil, err
+	}
+
+	if err = s.UserRepository.SetTOTPSecret(tx, data.ID, secret, time.Now()); err != nil {
+		s.Log.Errorf("error storing totp secret: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.TOTPEnrollResponse{
+		Secret:     secret,
+		OTPAuthURL: totp.URI(s.Config.MFAIssuer, data.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication and returns the recovery codes, they are only shown this once
+func (s *UserService) ConfirmTOTP(ctx context.Context, request *model.TOTPCodeRequest) (*model.RecoveryCodesResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return nil, e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByIDForUpdate(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		return nil, err
+	}
+
+	switch {
+	case data.TOTPEnabledAt != nil:
+		err = e.ErrMFAAlreadyEnabled
+		return nil, err
+	case data.TOTPSecret == nil:
+		err = e.ErrMFANotEnabled
+		return nil, err
+	}
+
+	counter, ok := totp.Validate(*data.TOTPSecret, request.Code, time.Now(), totpSkew)
+	if !ok {
+		err = e.ErrInvalidMFACode
+		return nil, err
+	}
+
+	if err = s.UserRepository.EnableTOTP(tx, data.ID, counter, time.Now()); err != nil {
+		s.Log.Errorf("error enabling totp: %v", err)
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(tx, data.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// DisableTOTP turns two-factor authentication off, it takes a current code or a recovery code
+func (s *UserService) DisableTOTP(ctx context.Context, request *model.TOTPCodeRequest) error {
+	if err := s.Validate.Struct(request); err != nil {
+		return e.ErrValidation
+	}
+
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByIDForUpdate(tx, userID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		return err
+	}
+
+	if data.TOTPEnabledAt == nil {
+		err = e.ErrMFANotEnabled
+		return err
+	}
+
+	ok, err := s.verifySecondFactor(tx, data, request.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = e.ErrInvalidMFACode
+		return err
+	}
+
+	if err = s.UserRepository.DisableTOTP(tx, data.ID, time.Now()); err != nil {
+		s.Log.Errorf("error disabling totp: %v", err)
+		return err
+	}
+
+	if err = s.RecoveryCodeRepository.DeleteByUserID(tx, data.ID); err != nil {
+		s.Log.Errorf("error deleting recovery codes: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// LoginMFA exchanges the challenge token of a login and a second factor for the real tokens
+func (s *UserService) LoginMFA(ctx context.Context, request *model.MFALoginRequest) (*model.TokenResponse, error) {
+	if err := s.Validate.Struct(request); err != nil {
+		return nil, e.ErrValidation
+	}
+
+	claims, err := s.JWTService.ValidateToken(request.MFAToken, jwt.TokenTypeMFA)
+	if err != nil {
+		s.Log.Errorf("failed to validate token: %v", err)
+		return nil, e.ErrCredential
+	}
+
+	revoked, err := s.Denylist.IsRevoked(ctx, claims.ID, claims.UserID, claims.IssuedAt.Time)
+	if err != nil {
+		s.Log.Errorf("error checking denylist: %v", err)
+		return nil, err
+	}
+	if revoked {
+		return nil, e.ErrCredential
+	}
+
+	// Wrong codes count as failed logins so the six digits cannot be brute forced
+	attemptKeys := loginAttemptKeys(claims.Email, request.IP)
+	if err := s.checkLoginThrottle(ctx, attemptKeys); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	data, err := s.UserRepository.GetByIDForUpdate(tx, claims.UserID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrCredential
+		}
+		return nil, err
+	}
+
+	if data.TOTPEnabledAt == nil || data.DisabledAt != nil {
+		err = e.ErrCredential
+		return nil, err
+	}
+
+	ok, err := s.verifySecondFactor(tx, data, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.recordLoginFailure(ctx, attemptKeys)
+		err = e.ErrInvalidMFACode
+		return nil, err
+	}
+
+	response, err := s.issueTokens(tx, data, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	// The challenge is single use
+	if err = s.Denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		s.Log.Errorf("error revoking mfa token: %v", err)
+	}
+	s.resetLoginAttempts(ctx, attemptKeys)
+
+	return response, nil
+}
+
+// verifySecondFactor accepts a TOTP code newer than the last accepted one, or an unused recovery code
+func (s *UserService) verifySecondFactor(tx *sqlx.Tx, user *entity.User, code string) (bool, error) {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	if len(code) == totp.Digits && user.TOTPSecret != nil {
+		counter, ok := totp.Validate(*user.TOTPSecret, code, time.Now(), totpSkew)
+		if !ok || (user.TOTPLastCounter != nil && counter <= *user.TOTPLastCounter) {
+			return false, nil
+		}
+
+		if err := s.UserRepository.UpdateTOTPCounter(tx, user.ID, counter); err != nil {
+			s.Log.Errorf("error updating totp counter: %v", err)
+			return false, err
+		}
+		return true, nil
+	}
+
+	recoveryCode, err := s.RecoveryCodeRepository.GetUnusedForUpdate(tx, user.ID, hashUserToken(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		s.Log.Errorf("error getting recovery code: %v", err)
+		return false, err
+	}
+
+	if err := s.RecoveryCodeRepository.MarkUsed(tx, recoveryCode.ID, time.Now()); err != nil {
+		s.Log.Errorf("error marking recovery code used: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// replaceRecoveryCodes drops the recovery codes of a user and returns a new set in plain text
+func (s *UserService) replaceRecoveryCodes(tx *sqlx.Tx, userID string) ([]string, error) {
+	if err := s.RecoveryCodeRepository.DeleteByUserID(tx, userID); err != nil {
+		s.Log.Errorf("error deleting recovery codes: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		for j := range raw {
+			raw[j] = recoveryCodeAlphabet[int(raw[j])%len(recoveryCodeAlphabet)]
+		}
+		code := string(raw)
+
+		err := s.RecoveryCodeRepository.Create(tx, &entity.RecoveryCode{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			CodeHash:  hashUserToken(code),
+			CreatedAt: now,
+		})
+		if err != nil {
+			s.Log.Errorf("error creating recovery code: %v", err)
+			return nil, err
+		}
+
+		codes[i] = code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+	}
+
+	return codes, nil
+}
@@ -12,38 +12,85 @@ import (
 	"github.com/savioruz/bake/internal/domain/entity"
 	"github.com/savioruz/bake/internal/domain/model"
 	"github.com/savioruz/bake/internal/repository"
+	"github.com/savioruz/bake/pkg/denylist"
 	e "github.com/savioruz/bake/pkg/error"
 	"github.com/savioruz/bake/pkg/helper"
 	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/savioruz/bake/pkg/mailer"
 	"github.com/savioruz/bake/pkg/middleware"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// UserConfig holds the settings of sessions and of the account emails
+type UserConfig struct {
+	AccessExpiry         time.Duration
+	AppURL               string
+	PasswordResetURL     string
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginBackoffBase      time.Duration
+	LoginLockout          time.Duration
+
+	// MFAIssuer names the account in authenticator apps
+	MFAIssuer string
+}
+
+// dummyPasswordHash is compared against when the email is unknown, so that login takes as long as
+// it does for a wrong password and the response time does not tell which emails are registered
+const dummyPasswordHash = "$2a$10$oQWiXx8/uYWgIdtUlou1EuhDZhdYqBH/hAJud4pdrVCjYDCciy28e"
+
 type UserService struct {
-	UserRepository    *repository.UserRepository
-	AddressRepository *repository.AddressRepository
-	DB                *sqlx.DB
-	Log               *logrus.Logger
-	Validate          *validator.Validate
-	JWTService        jwt.JWTService
+	UserRepository         *repository.UserRepository
+	AddressRepository      *repository.AddressRepository
+	RefreshTokenRepository *repository.RefreshTokenRepository
+	UserTokenRepository    *repository.UserTokenRepository
+	RoleRepository         *repository.RoleRepository
+	LoginAttemptRepository *repository.LoginAttemptRepository
+	RecoveryCodeRepository *repository.RecoveryCodeRepository
+	DB                     *sqlx.DB
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
+	JWTService             jwt.JWTService
+	Denylist               denylist.Store
+	Mailer                 mailer.Mailer
+	Config                 *UserConfig
 }
 
 func NewUserService(
 	userRepo *repository.UserRepository,
 	addressRepo *repository.AddressRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	userTokenRepo *repository.UserTokenRepository,
+	roleRepo *repository.RoleRepository,
+	loginAttemptRepo *repository.LoginAttemptRepository,
+	recoveryCodeRepo *repository.RecoveryCodeRepository,
 	db *sqlx.DB,
 	log *logrus.Logger,
 	validate *validator.Validate,
 	jwtService jwt.JWTService,
+	denylist denylist.Store,
+	mailer mailer.Mailer,
+	config *UserConfig,
 ) *UserService {
 	return &UserService{
-		UserRepository:    userRepo,
-		AddressRepository: addressRepo,
-		DB:                db,
-		Log:               log,
-		Validate:          validate,
-		JWTService:        jwtService,
+		UserRepository:         userRepo,
+		AddressRepository:      addressRepo,
+		RefreshTokenRepository: refreshTokenRepo,
+		UserTokenRepository:    userTokenRepo,
+		RoleRepository:         roleRepo,
+		LoginAttemptRepository: loginAttemptRepo,
+		RecoveryCodeRepository: recoveryCodeRepo,
+		DB:                     db,
+		Log:                    log,
+		Validate:               validate,
+		JWTService:             jwtService,
+		Denylist:               denylist,
+		Mailer:                 mailer,
+		Config:                 config,
 	}
 }
 
@@ -73,21 +120,6 @@ func (s *UserService) CreateUser(ctx context.Context, request *model.UserRegiste
 		return nil, err
 	}
 
-	first := &entity.User{}
-	err = s.UserRepository.GetFirst(tx, first)
-
-	// Role
-	var role string
-	switch {
-	case errors.Is(err, sql.ErrNoRows):
-		role = "admin"
-	case err != nil:
-		s.Log.Errorf("error getting first user: %v", err)
-		return nil, err
-	default:
-		role = "user"
-	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
 		s.Log.Errorf("error hashing password: %v", err)
@@ -101,18 +133,19 @@ func (s *UserService) CreateUser(ctx context.Context, request *model.UserRegiste
 		Password:  string(hashedPassword),
 		Name:      request.Name,
 		Phone:     request.Phone,
-		Role:      role,
+		Role:      RoleUser,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.UserRepository.Create(tx, data); err != nil {
+	if err = s.UserRepository.Create(tx, data); err != nil {
 		s.Log.Errorf("error creating user: %v", err)
 		return nil, err
 	}
 
 	var addressResp *model.AddressResponse
 	if request.Address != nil {
+		now := time.Now()
 		address := &entity.Address{
 			ID:          uuid.NewString(),
 			UserID:      userID,
@@ -121,24 +154,22 @@ func (s *UserService) CreateUser(ctx context.Context, request *model.UserRegiste
 			State:       request.Address.State,
 			PostalCode:  request.Address.PostalCode,
 			Country:     request.Address.Country,
+			IsDefault:   true,
+			CreatedAt:   now,
+			UpdatedAt:   now,
 		}
 
-		if err := s.AddressRepository.Create(tx, address); err != nil {
+		if err = s.AddressRepository.Create(tx, address); err != nil {
 			s.Log.Errorf("error creating address: %v", err)
 			return nil, err
 		}
 
-		addressResp = &model.AddressResponse{
-			ID:          address.ID,
-			UserID:      address.UserID,
-			AddressLine: address.AddressLine,
-			City:        address.City,
-			State:       address.State,
-			PostalCode:  address.PostalCode,
-			Country:     address.Country,
-			CreatedAt:   helper.FormatTime(address.CreatedAt),
-			UpdatedAt:   helper.FormatTime(address.UpdatedAt),
-		}
+		addressResp = toAddressResponse(address)
+	}
+
+	verificationToken, err := s.issueUserToken(tx, data.ID, UserTokenPurposeEmailVerification, s.Config.EmailVerificationTTL)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -146,23 +177,25 @@ func (s *UserService) CreateUser(ctx context.Context, request *model.UserRegiste
 		return nil, err
 	}
 
-	return &model.UserResponse{
-		ID:        data.ID,
-		Email:     data.Email,
-		Name:      data.Name,
-		Phone:     data.Phone,
-		Role:      data.Role,
-		CreatedAt: helper.FormatTime(data.CreatedAt),
-		UpdatedAt: helper.FormatTime(data.UpdatedAt),
-		Address:   addressResp,
-	}, nil
+	// A failed email does not undo the registration, the user can ask for a new one
+	s.sendVerificationEmail(ctx, data, verificationToken)
+
+	response := toUserResponse(data)
+	response.Address = addressResp
+	return response, nil
 }
 
-func (s *UserService) Login(ctx context.Context, request *model.UserLoginRequest) (*model.TokenResponse, error) {
+// Login checks the password and returns the tokens, or a challenge to exchange in LoginMFA when two-factor authentication is enabled
+func (s *UserService) Login(ctx context.Context, request *model.UserLoginRequest) (*model.LoginResponse, error) {
 	if err := s.Validate.Struct(request); err != nil {
 		return nil, e.ErrValidation
 	}
 
+	attemptKeys := loginAttemptKeys(request.Email, request.IP)
+	if err := s.checkLoginThrottle(ctx, attemptKeys); err != nil {
+		return nil, err
+	}
+
 	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -179,54 +212,266 @@ func (s *UserService) Login(ctx context.Context, request *model.UserLoginRequest
 	if err != nil {
 		s.Log.Errorf("error getting user by email: %v", err)
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, e.ErrUserNotFound
+			bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(request.Password))
+			s.recordLoginFailure(ctx, attemptKeys)
+			err = e.ErrCredential
 		}
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(request.Password)); err != nil {
+	if err = bcrypt.CompareHashAndPassword([]byte(data.Password), []byte(request.Password)); err != nil {
 		s.Log.Errorf("error comparing password: %v", err)
-		return nil, e.ErrCredential
+		s.recordLoginFailure(ctx, attemptKeys)
+		err = e.ErrCredential
+		return nil, err
 	}
 
-	accessToken, err := s.JWTService.GenerateAccessToken(data.ID, data.Email, data.Role)
-	if err != nil {
-		s.Log.Errorf("error generating access token: %v", err)
+	if data.DisabledAt != nil {
+		err = e.ErrAccountDisabled
 		return nil, err
 	}
 
-	refreshToken, err := s.JWTService.GenerateRefreshToken(data.ID, data.Email, data.Role)
+	// The counters are only reset once the second factor is verified too
+	if data.TOTPEnabledAt != nil {
+		var mfaToken string
+		mfaToken, _, err = s.JWTService.GenerateMFAToken(data.ID, data.Email, data.Role)
+		if err != nil {
+			s.Log.Errorf("error generating mfa token: %v", err)
+			return nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			s.Log.Errorf("error committing transaction: %v", err)
+			return nil, err
+		}
+
+		return &model.LoginResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	// Every login starts a new refresh token family
+	response, err := s.issueTokens(tx, data, uuid.NewString())
 	if err != nil {
-		s.Log.Errorf("error generating refresh token: %v", err)
 		return nil, err
 	}
 
-	return &model.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	s.resetLoginAttempts(ctx, attemptKeys)
+
+	return &model.LoginResponse{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
 	}, nil
 }
 
+// RefreshToken rotates a refresh token, presenting an already used one revokes its whole family
 func (s *UserService) RefreshToken(ctx context.Context, request *model.RefreshTokenRequest) (*model.TokenResponse, error) {
 	if err := s.Validate.Struct(request); err != nil {
 		return nil, e.ErrValidation
 	}
 
-	claims, err := s.JWTService.ValidateToken(request.RefreshToken)
+	claims, err := s.JWTService.ValidateToken(request.RefreshToken, jwt.TokenTypeRefresh)
 	if err != nil {
 		s.Log.Errorf("failed to validate token: %v", err)
 		return nil, e.ErrCredential
 	}
 
-	accessToken, err := s.JWTService.GenerateAccessToken(claims.UserID, claims.Email, claims.Role)
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	stored, err := s.RefreshTokenRepository.GetByIDForUpdate(tx, claims.ID)
+	if err != nil {
+		s.Log.Errorf("error getting refresh token: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrCredential
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		err = e.ErrCredential
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil {
+		// A used token coming back means it leaked, so the whole family is revoked
+		s.Log.Warnf("refresh token %s reused, revoking family %s", stored.ID, stored.FamilyID)
+		if err = s.RefreshTokenRepository.RevokeFamily(tx, stored.FamilyID, now); err != nil {
+			s.Log.Errorf("error revoking refresh token family: %v", err)
+			return nil, err
+		}
+		if err = tx.Commit(); err != nil {
+			s.Log.Errorf("error committing transaction: %v", err)
+			return nil, err
+		}
+		return nil, e.ErrCredential
+	}
+
+	if err = s.RefreshTokenRepository.MarkUsed(tx, stored.ID, now); err != nil {
+		s.Log.Errorf("error marking refresh token used: %v", err)
+		return nil, err
+	}
+
+	data, err := s.UserRepository.GetByID(tx, stored.UserID)
+	if err != nil {
+		s.Log.Errorf("error getting user by id: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = e.ErrCredential
+		}
+		return nil, err
+	}
+
+	if data.DisabledAt != nil {
+		err = e.ErrCredential
+		return nil, err
+	}
+
+	response, err := s.issueTokens(tx, data, stored.FamilyID)
 	if err != nil {
-		s.Log.Errorf("failed to generate access token: %v", err)
 		return nil, err
 	}
 
-	refreshToken, err := s.JWTService.GenerateRefreshToken(claims.UserID, claims.Email, claims.Role)
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Logout revokes the access token of the request and, when given, the refresh token family of the session
+func (s *UserService) Logout(ctx context.Context, request *model.LogoutRequest) error {
+	userID := middleware.GetUserIDFromContext(ctx)
+	tokenID := middleware.GetTokenIDFromContext(ctx)
+	if userID == "" || tokenID == "" {
+		return e.ErrUnauthorized
+	}
+
+	if request.RefreshToken != "" {
+		claims, err := s.JWTService.ValidateToken(request.RefreshToken, jwt.TokenTypeRefresh)
+		if err != nil || claims.UserID != userID {
+			s.Log.Errorf("failed to validate refresh token on logout: %v", err)
+			return e.ErrCredential
+		}
+
+		tx, err := s.DB.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+		}()
+
+		stored, err := s.RefreshTokenRepository.GetByIDForUpdate(tx, claims.ID)
+		if err != nil {
+			s.Log.Errorf("error getting refresh token: %v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				err = e.ErrCredential
+			}
+			return err
+		}
+
+		if err = s.RefreshTokenRepository.RevokeFamily(tx, stored.FamilyID, time.Now()); err != nil {
+			s.Log.Errorf("error revoking refresh token family: %v", err)
+			return err
+		}
+
+		if err = tx.Commit(); err != nil {
+			s.Log.Errorf("error committing transaction: %v", err)
+			return err
+		}
+	}
+
+	if err := s.Denylist.Revoke(ctx, tokenID, middleware.GetTokenExpiresAtFromContext(ctx)); err != nil {
+		s.Log.Errorf("error revoking access token: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// LogoutAll revokes every access and refresh token the current user holds
+func (s *UserService) LogoutAll(ctx context.Context) error {
+	userID := middleware.GetUserIDFromContext(ctx)
+	if userID == "" {
+		return e.ErrUnauthorized
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		s.Log.Errorf("failed to generate refresh token: %v", err)
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}()
+
+	now := time.Now()
+	if err = s.RefreshTokenRepository.RevokeByUserID(tx, userID, now); err != nil {
+		s.Log.Errorf("error revoking refresh tokens: %v", err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return err
+	}
+
+	// Access tokens issued before now are all expired once the access expiry has passed
+	if err = s.Denylist.RevokeUser(ctx, userID, now, now.Add(s.Config.AccessExpiry)); err != nil {
+		s.Log.Errorf("error revoking access tokens: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// issueTokens signs a token pair and stores the refresh token in the given family
+func (s *UserService) issueTokens(tx *sqlx.Tx, user *entity.User, familyID string) (*model.TokenResponse, error) {
+	accessToken, err := s.JWTService.GenerateAccessToken(user.ID, user.Email, user.Role)
+	if err != nil {
+		s.Log.Errorf("error generating access token: %v", err)
+		return nil, err
+	}
+
+	refreshToken, claims, err := s.JWTService.GenerateRefreshToken(user.ID, user.Email, user.Role)
+	if err != nil {
+		s.Log.Errorf("error generating refresh token: %v", err)
+		return nil, err
+	}
+
+	err = s.RefreshTokenRepository.Create(tx, &entity.RefreshToken{
+		ID:        claims.ID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: claims.ExpiresAt.Time,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		s.Log.Errorf("error storing refresh token: %v", err)
 		return nil, err
 	}
 
@@ -259,31 +504,39 @@ func (s *UserService) Me(ctx context.Context) (*model.UserResponse, error) {
 		return nil, err
 	}
 
-	address, err := s.AddressRepository.GetByUserID(tx, userID)
-	if err != nil {
+	// Users may register without an address
+	var addressResp *model.AddressResponse
+	address, err := s.AddressRepository.GetDefaultByUserID(tx, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = nil
+	case err != nil:
 		return nil, err
+	default:
+		addressResp = toAddressResponse(address)
 	}
 
-	addressResp := &model.AddressResponse{
-		ID:          address.ID,
-		UserID:      address.UserID,
-		AddressLine: address.AddressLine,
-		City:        address.City,
-		State:       address.State,
-		PostalCode:  address.PostalCode,
-		Country:     address.Country,
-		CreatedAt:   helper.FormatTime(address.CreatedAt),
-		UpdatedAt:   helper.FormatTime(address.UpdatedAt),
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
 	}
 
+	response := toUserResponse(data)
+	response.Address = addressResp
+	return response, nil
+}
+
+func toUserResponse(user *entity.User) *model.UserResponse {
 	return &model.UserResponse{
-		ID:        data.ID,
-		Email:     data.Email,
-		Name:      data.Name,
-		Phone:     data.Phone,
-		Role:      data.Role,
-		CreatedAt: helper.FormatTime(data.CreatedAt),
-		UpdatedAt: helper.FormatTime(data.UpdatedAt),
-		Address:   addressResp,
-	}, nil
+		ID:            user.ID,
+		Email:         user.Email,
+		Name:          user.Name,
+		Phone:         user.Phone,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		TwoFactor:     user.TOTPEnabledAt != nil,
+		Disabled:      user.DisabledAt != nil,
+		CreatedAt:     helper.FormatTime(user.CreatedAt),
+		UpdatedAt:     helper.FormatTime(user.UpdatedAt),
+	}
 }
@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/savioruz/bake/internal/domain/model"
+	"github.com/savioruz/bake/internal/repository"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+type VariantService struct {
+	ProductRepository *repository.ProductRepository
+	VariantRepository *repository.VariantRepository
+	OptionRepository  *repository.OptionRepository
+	DB                *sqlx.DB
+	Log               *logrus.Logger
+	Validate          *validator.Validate
+}
+
+func NewVariantService(
+	productRepo *repository.ProductRepository,
+	variantRepo *repository.VariantRepository,
+	optionRepo *repository.OptionRepository,
+	db *sqlx.DB,
+	log *logrus.Logger,
+	validate *validator.Validate,
+) *VariantService {
+	return &VariantService{
+		ProductRepository: productRepo,
+		VariantRepository: variantRepo,
+		OptionRepository:  optionRepo,
+		DB:                db,
+		Log:               log,
+		Validate:          validate,
+	}
+}
+
+// GetOptions lists the variants and option groups a customer can choose from for a product
+func (s *VariantService) GetOptions(ctx context.Context, request *model.GetProductRequest) (*model.SuccessResponse[*model.ProductOptionsResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	product, err := s.getProduct(tx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !product.Available() {
+		err = e.ErrNotFound
+		return nil, err
+	}
+
+	variants, err := s.VariantRepository.GetByProductID(tx, product.ID)
+	if err != nil {
+		s.Log.Errorf("error getting variants: %v", err)
+		return nil, err
+	}
+
+	groups, err := s.OptionRepository.GetGroupsByProductID(tx, product.ID)
+	if err != nil {
+		s.Log.Errorf("error getting option groups: %v", err)
+		return nil, err
+	}
+
+	groupIDs := make([]string, len(groups))
+	for i, group := range groups {
+		groupIDs[i] = group.ID
+	}
+
+	options, err := s.OptionRepository.GetByGroupIDs(tx, groupIDs)
+	if err != nil {
+		s.Log.Errorf("error getting options: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	optionsByGroup := make(map[string][]entity.ProductOption, len(groups))
+	for _, option := range options {
+		optionsByGroup[option.GroupID] = append(optionsByGroup[option.GroupID], option)
+	}
+
+	optionsResponse := &model.ProductOptionsResponse{
+		ProductID:    product.ID,
+		Variants:     make([]model.VariantResponse, len(variants)),
+		OptionGroups: make([]model.OptionGroupResponse, len(groups)),
+	}
+	for i := range variants {
+		optionsResponse.Variants[i] = toVariantResponse(product, &variants[i])
+	}
+	for i := range groups {
+		optionsResponse.OptionGroups[i] = toOptionGroupResponse(&groups[i], optionsByGroup[groups[i].ID])
+	}
+
+	return &model.SuccessResponse[*model.ProductOptionsResponse]{
+		Data: &optionsResponse,
+	}, nil
+}
+
+// SaveVariant creates a variant, or updates the variant with the request id when it is set
+func (s *VariantService) SaveVariant(ctx context.Context, request *model.SaveVariantRequest) (*model.SuccessResponse[model.VariantResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	product, err := s.getProduct(tx, request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A discount may not push the variant below zero
+	if product.Price+request.PriceDelta < 0 {
+		s.Log.Errorf("variant price of product %s would be negative", product.ID)
+		err = e.ErrValidation
+		return nil, err
+	}
+
+	existing, err := s.VariantRepository.GetBySKU(tx, request.SKU)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = nil
+	case err != nil:
+		s.Log.Errorf("error getting variant by sku: %v", err)
+		return nil, err
+	case existing.ID != request.ID:
+		err = e.ErrSKUExists
+		return nil, err
+	}
+
+	now := time.Now()
+	var data *entity.ProductVariant
+	if request.ID == "" {
+		data = &entity.ProductVariant{
+			ID:         uuid.NewString(),
+			ProductID:  product.ID,
+			SKU:        request.SKU,
+			Name:       request.Name,
+			PriceDelta: request.PriceDelta,
+			Stock:      request.Stock,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		err = s.VariantRepository.Create(tx, data)
+	} else {
+		data, err = s.getVariant(tx, product.ID, request.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		data.SKU = request.SKU
+		data.Name = request.Name
+		data.PriceDelta = request.PriceDelta
+		data.Stock = request.Stock
+		data.UpdatedAt = now
+		err = s.VariantRepository.Update(tx, data)
+	}
+	if err != nil {
+		s.Log.Errorf("error saving variant: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	variantResponse := toVariantResponse(product, data)
+	return &model.SuccessResponse[model.VariantResponse]{
+		Data: &variantResponse,
+	}, nil
+}
+
+// DeleteVariant removes a variant, orders keep the variant name they were placed with
+func (s *VariantService) DeleteVariant(ctx context.Context, request *model.GetVariantRequest) (*model.SuccessResponse[*model.GetVariantRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.getVariant(tx, request.ProductID, request.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.VariantRepository.Delete(tx, request.ID); err != nil {
+		s.Log.Errorf("error deleting variant: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.GetVariantRequest]{
+		Data: &request,
+	}, nil
+}
+
+// SaveOptionGroup creates an option group, or replaces the group with the request id when it is set
+func (s *VariantService) SaveOptionGroup(ctx context.Context, request *model.SaveOptionGroupRequest) (*model.SuccessResponse[model.OptionGroupResponse], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	product, err := s.getProduct(tx, request.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var group *entity.ProductOptionGroup
+	existingOptions := make(map[string]bool)
+	if request.ID == "" {
+		group = &entity.ProductOptionGroup{
+			ID:        uuid.NewString(),
+			ProductID: product.ID,
+			Name:      request.Name,
+			Required:  request.Required,
+			MaxSelect: request.MaxSelect,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		err = s.OptionRepository.CreateGroup(tx, group)
+	} else {
+		group, err = s.getOptionGroup(tx, product.ID, request.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var options []entity.ProductOption
+		options, err = s.OptionRepository.GetByGroupIDs(tx, []string{group.ID})
+		if err != nil {
+			s.Log.Errorf("error getting options: %v", err)
+			return nil, err
+		}
+		for _, option := range options {
+			existingOptions[option.ID] = true
+		}
+
+		group.Name = request.Name
+		group.Required = request.Required
+		group.MaxSelect = request.MaxSelect
+		group.UpdatedAt = now
+		err = s.OptionRepository.UpdateGroup(tx, group)
+	}
+	if err != nil {
+		s.Log.Errorf("error saving option group: %v", err)
+		return nil, err
+	}
+
+	keepIDs := make([]string, 0, len(request.Options))
+	for _, option := range request.Options {
+		if option.ID != "" && !existingOptions[option.ID] {
+			s.Log.Errorf("option %s does not belong to group %s", option.ID, group.ID)
+			err = e.ErrInvalidOptions
+			return nil, err
+		}
+		if option.ID != "" {
+			keepIDs = append(keepIDs, option.ID)
+		}
+	}
+
+	if err = s.OptionRepository.DeleteExcept(tx, group.ID, keepIDs); err != nil {
+		s.Log.Errorf("error removing options: %v", err)
+		return nil, err
+	}
+
+	options := make([]entity.ProductOption, len(request.Options))
+	for i, option := range request.Options {
+		options[i] = entity.ProductOption{
+			ID:        option.ID,
+			GroupID:   group.ID,
+			Name:      option.Name,
+			Price:     option.Price,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		if option.ID == "" {
+			options[i].ID = uuid.NewString()
+			err = s.OptionRepository.Create(tx, &options[i])
+		} else {
+			err = s.OptionRepository.Update(tx, &options[i])
+		}
+		if err != nil {
+			s.Log.Errorf("error saving option: %v", err)
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	groupResponse := toOptionGroupResponse(group, options)
+	return &model.SuccessResponse[model.OptionGroupResponse]{
+		Data: &groupResponse,
+	}, nil
+}
+
+// DeleteOptionGroup removes an option group with its options, orders keep their snapshots
+func (s *VariantService) DeleteOptionGroup(ctx context.Context, request *model.GetOptionGroupRequest) (*model.SuccessResponse[*model.GetOptionGroupRequest], error) {
+	if err := s.Validate.Struct(request); err != nil {
+		s.Log.Errorf("validation error for request: %v", err)
+		return nil, e.ErrValidation
+	}
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		s.Log.Errorf("error beginning transaction: %v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.Log.Errorf("rolling back transaction due to error: %v", err)
+			tx.Rollback()
+			return
+		}
+	}()
+
+	if _, err = s.getOptionGroup(tx, request.ProductID, request.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.OptionRepository.DeleteGroup(tx, request.ID); err != nil {
+		s.Log.Errorf("error deleting option group: %v", err)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		s.Log.Errorf("error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &model.SuccessResponse[*model.GetOptionGroupRequest]{
+		Data: &request,
+	}, nil
+}
+
+func (s *VariantService) getProduct(tx *sqlx.Tx, id string) (*entity.Product, error) {
+	product, err := s.ProductRepository.GetByID(tx, id)
+	if err != nil {
+		s.Log.Errorf("error getting product: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// getVariant loads a variant and hides variants of other products behind ErrVariantNotFound
+func (s *VariantService) getVariant(tx *sqlx.Tx, productID, id string) (*entity.ProductVariant, error) {
+	variant, err := s.VariantRepository.GetByID(tx, id)
+	if err != nil {
+		s.Log.Errorf("error getting variant: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrVariantNotFound
+		}
+		return nil, err
+	}
+
+	if variant.ProductID != productID {
+		return nil, e.ErrVariantNotFound
+	}
+
+	return variant, nil
+}
+
+func (s *VariantService) getOptionGroup(tx *sqlx.Tx, productID, id string) (*entity.ProductOptionGroup, error) {
+	group, err := s.OptionRepository.GetGroupByID(tx, id)
+	if err != nil {
+		s.Log.Errorf("error getting option group: %v", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, e.ErrOptionGroupNotFound
+		}
+		return nil, err
+	}
+
+	if group.ProductID != productID {
+		return nil, e.ErrOptionGroupNotFound
+	}
+
+	return group, nil
+}
+
+func toVariantResponse(product *entity.Product, variant *entity.ProductVariant) model.VariantResponse {
+	return model.VariantResponse{
+		ID:         variant.ID,
+		ProductID:  variant.ProductID,
+		SKU:        variant.SKU,
+		Name:       variant.Name,
+		PriceDelta: variant.PriceDelta,
+		Price:      product.Price + variant.PriceDelta,
+		Stock:      variant.Stock,
+	}
+}
+
+func toOptionGroupResponse(group *entity.ProductOptionGroup, options []entity.ProductOption) model.OptionGroupResponse {
+	optionResponses := make([]model.OptionResponse, len(options))
+	for i, option := range options {
+		optionResponses[i] = model.OptionResponse{
+			ID:    option.ID,
+			Name:  option.Name,
+			Price: option.Price,
+		}
+	}
+
+	return model.OptionGroupResponse{
+		ID:        group.ID,
+		ProductID: group.ProductID,
+		Name:      group.Name,
+		Required:  group.Required,
+		MaxSelect: group.MaxSelect,
+		Options:   optionResponses,
+	}
+}
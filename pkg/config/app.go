@@ -1,6 +1,8 @@
 package config
 
 import (
+	"context"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/jmoiron/sqlx"
 	"github.com/savioruz/bake/internal/builder"
@@ -8,7 +10,10 @@ import (
 	"github.com/savioruz/bake/internal/repository"
 	"github.com/savioruz/bake/internal/service"
 	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/savioruz/bake/pkg/mailer"
 	"github.com/savioruz/bake/pkg/middleware"
+	"github.com/savioruz/bake/pkg/payment"
+	"github.com/savioruz/bake/pkg/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -18,41 +23,98 @@ type BootstrapConfig struct {
 	Log       *logrus.Logger
 	Validator *validator.Validate
 	JWT       *jwt.JWTConfig
+	Payment   payment.PaymentGateway
+	Mailer    mailer.Mailer
+	Storage   storage.BlobStore
 	Viper     *viper.Viper
 }
 
 func Bootstrap(c *BootstrapConfig) error {
-	// Initialize services
-	jwtService := jwt.NewJWTService(c.JWT)
+	// Background jobs stop once the server has stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService, c.Log)
+	// Initialize services
+	jwtService, err := jwt.NewJWTService(c.JWT)
+	if err != nil {
+		return err
+	}
 
 	// Initialize repositories
 	userRepository := repository.NewUserRepository(c.DB)
 	addressRepository := repository.NewAddressRepository(c.DB)
 	productRepository := repository.NewProductRepository(c.DB)
 	orderRepository := repository.NewOrderRepository(c.DB)
+	orderItemRepository := repository.NewOrderItemRepository(c.DB)
+	cartRepository := repository.NewCartRepository(c.DB)
+	paymentRepository := repository.NewPaymentRepository(c.DB)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(c.DB)
+	roleRepository := repository.NewRoleRepository(c.DB)
+	userTokenRepository := repository.NewUserTokenRepository(c.DB)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(c.DB)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(c.DB)
+	categoryRepository := repository.NewCategoryRepository(c.DB)
+	tagRepository := repository.NewTagRepository(c.DB)
+	variantRepository := repository.NewVariantRepository(c.DB)
+	optionRepository := repository.NewOptionRepository(c.DB)
+	productImageRepository := repository.NewProductImageRepository(c.DB)
 
 	// Initialize services
-	userService := service.NewUserService(userRepository, addressRepository, c.DB, c.Log, c.Validator, jwtService)
-	productService := service.NewProductService(productRepository, c.DB, c.Log, c.Validator)
-	orderService := service.NewOrderService(orderRepository, productRepository, addressRepository, c.DB, c.Log, c.Validator)
+	denylistStore := NewDenylist(ctx, c.Viper, c.DB, c.JWT, c.Log)
+	roleService := service.NewRoleService(roleRepository, userRepository, c.DB, c.Log, c.Validator, roleCacheTTL(c.Viper))
+	userService := service.NewUserService(userRepository, addressRepository, refreshTokenRepository, userTokenRepository, roleRepository, loginAttemptRepository, recoveryCodeRepository, c.DB, c.Log, c.Validator, jwtService, denylistStore, c.Mailer, NewUserConfig(c.Viper, c.JWT))
+	productService := service.NewProductService(productRepository, categoryRepository, tagRepository, productImageRepository, c.Storage, c.DB, c.Log, c.Validator)
+	orderService := service.NewOrderService(orderRepository, orderItemRepository, productRepository, variantRepository, optionRepository, addressRepository, userRepository, paymentRepository, c.Payment, c.DB, c.Log, c.Validator, c.Viper.GetBool("REQUIRE_VERIFIED_EMAIL"))
+	addressService := service.NewAddressService(addressRepository, c.DB, c.Log, c.Validator)
+	cartService := service.NewCartService(cartRepository, productRepository, orderService, c.DB, c.Log, c.Validator)
+	paymentService := service.NewPaymentService(paymentRepository, orderService, c.Payment, c.DB, c.Log, c.Validator)
+	categoryService := service.NewCategoryService(categoryRepository, c.DB, c.Log, c.Validator)
+	tagService := service.NewTagService(tagRepository, c.DB, c.Log, c.Validator)
+	variantService := service.NewVariantService(productRepository, variantRepository, optionRepository, c.DB, c.Log, c.Validator)
+	productImageService := service.NewProductImageService(productRepository, productImageRepository, c.Storage, c.DB, c.Log, c.Validator, uploadMaxSize(c.Viper))
+
+	// Initialize middleware
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, denylistStore, roleService, c.Log)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(
+		repository.NewIdempotencyRepository(c.DB),
+		idempotencyTTL(c.Viper),
+		c.Log,
+	)
+	go idempotencyMiddleware.RunGC(ctx, idempotencyGCInterval(c.Viper))
 
 	// Initialize handlers
-	userHandler := handler.NewUserHandler(userService, c.Log)
+	userHandler := handler.NewUserHandler(userService, trustedProxies(c.Viper, c.Log), c.Log)
 	productHandler := handler.NewProductHandler(productService, c.Log)
 	orderHandler := handler.NewOrderHandler(orderService, c.Log)
+	cartHandler := handler.NewCartHandler(cartService, c.Log)
+	addressHandler := handler.NewAddressHandler(addressService, c.Log)
+	paymentHandler := handler.NewPaymentHandler(paymentService, c.Log)
+	jwksHandler := handler.NewJWKSHandler(jwtService, c.Log)
+	roleHandler := handler.NewRoleHandler(roleService, c.Log)
+	categoryHandler := handler.NewCategoryHandler(categoryService, c.Log)
+	tagHandler := handler.NewTagHandler(tagService, c.Log)
+	variantHandler := handler.NewVariantHandler(variantService, c.Log)
+	productImageHandler := handler.NewProductImageHandler(productImageService, c.Log)
 
 	// Initialize server
 	server := NewServer(c.Viper, c.Log)
 
 	// Register routes
 	routeConfig := &builder.Config{
-		AuthMiddleware: authMiddleware,
-		UserHandler:    userHandler,
-		ProductHandler: productHandler,
-		OrderHandler:   orderHandler,
+		AuthMiddleware:        authMiddleware,
+		IdempotencyMiddleware: idempotencyMiddleware,
+		UserHandler:           userHandler,
+		ProductHandler:        productHandler,
+		OrderHandler:          orderHandler,
+		CartHandler:           cartHandler,
+		AddressHandler:        addressHandler,
+		PaymentHandler:        paymentHandler,
+		RoleHandler:           roleHandler,
+		CategoryHandler:       categoryHandler,
+		TagHandler:            tagHandler,
+		VariantHandler:        variantHandler,
+		ProductImageHandler:   productImageHandler,
+		JWKSHandler:           jwksHandler,
 	}
 
 	publicRoutes := builder.PublicRoutes(routeConfig)
@@ -62,6 +124,14 @@ func Bootstrap(c *BootstrapConfig) error {
 	allRoutes = append(allRoutes, publicRoutes...)
 	allRoutes = append(allRoutes, privateRoutes...)
 	allRoutes = append(allRoutes, swaggerRoutes...)
+	if paymentSimulation(c.Viper, c.Log) {
+		allRoutes = append(allRoutes, builder.SandboxRoutes(routeConfig)...)
+	}
+
+	// Local uploads are served by the app itself, s3 serves its own files
+	if localStore, ok := c.Storage.(*storage.LocalStore); ok {
+		allRoutes = append(allRoutes, builder.UploadRoutes(localStore.Dir())...)
+	}
 	server.RegisterRoutes(allRoutes)
 
 	// Start server
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/savioruz/bake/pkg/denylist"
+	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const defaultDenylistGCInterval = 10 * time.Minute
+
+// NewDenylist builds the token denylist store and starts its background garbage collection, which stops
+// with ctx. Entries outlive their token by the JWT leeway because verification still accepts it until then.
+func NewDenylist(ctx context.Context, viper *viper.Viper, db *sqlx.DB, jwtConfig *jwt.JWTConfig, log *logrus.Logger) denylist.Store {
+	var store denylist.Store
+	switch viper.GetString("DENYLIST_STORE") {
+	case "", "sql":
+		store = denylist.NewSQLStore(db, jwtConfig.Leeway)
+	case "memory":
+		store = denylist.NewMemoryStore(jwtConfig.Leeway)
+	default:
+		log.Fatalf("Unknown denylist store: %s", viper.GetString("DENYLIST_STORE"))
+		return nil
+	}
+
+	interval := viper.GetDuration("DENYLIST_GC_INTERVAL")
+	if interval <= 0 {
+		interval = defaultDenylistGCInterval
+	}
+	go denylist.RunGC(ctx, store, interval, log)
+
+	return store
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultIdempotencyTTL        = 24 * time.Hour
+	defaultIdempotencyGCInterval = 10 * time.Minute
+)
+
+func idempotencyTTL(viper *viper.Viper) time.Duration {
+	if ttl := viper.GetDuration("IDEMPOTENCY_TTL"); ttl > 0 {
+		return ttl
+	}
+	return defaultIdempotencyTTL
+}
+
+func idempotencyGCInterval(viper *viper.Viper) time.Duration {
+	if interval := viper.GetDuration("IDEMPOTENCY_GC_INTERVAL"); interval > 0 {
+		return interval
+	}
+	return defaultIdempotencyGCInterval
+}
@@ -1,14 +1,85 @@
 package config
 
 import (
+	"crypto"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func NewJWT(viper *viper.Viper) *jwt.JWTConfig {
-	return &jwt.JWTConfig{
+const (
+	defaultMFAExpiry = 5 * time.Minute
+	defaultJWTIssuer = "bake-api"
+)
+
+func NewJWT(viper *viper.Viper, log *logrus.Logger) *jwt.JWTConfig {
+	config := &jwt.JWTConfig{
 		Secret:        viper.GetString("JWT_SECRET"),
+		Algorithm:     viper.GetString("JWT_ALGORITHM"),
+		KeyID:         viper.GetString("JWT_KEY_ID"),
+		PublicKeys:    make(map[string]crypto.PublicKey),
 		AccessExpiry:  viper.GetDuration("JWT_ACCESS_EXPIRY"),
 		RefreshExpiry: viper.GetDuration("JWT_REFRESH_EXPIRY"),
+		MFAExpiry:     viper.GetDuration("JWT_MFA_EXPIRY"),
+		Issuer:        viper.GetString("JWT_ISSUER"),
+		Audience:      viper.GetString("JWT_AUDIENCE"),
+		Leeway:        viper.GetDuration("JWT_LEEWAY"),
+	}
+
+	if config.MFAExpiry <= 0 {
+		config.MFAExpiry = defaultMFAExpiry
+	}
+	// Tokens are always issued and verified with an issuer and an audience
+	if config.Issuer == "" {
+		config.Issuer = defaultJWTIssuer
+	}
+	if config.Audience == "" {
+		config.Audience = config.Issuer
+	}
+
+	if path := viper.GetString("JWT_PRIVATE_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read JWT private key: %v", err)
+			return nil
+		}
+
+		config.PrivateKey, err = jwt.ParsePrivateKeyPEM(config.Algorithm, data)
+		if err != nil {
+			log.Fatalf("Failed to parse JWT private key: %v", err)
+			return nil
+		}
+	}
+
+	// Retired keys are listed as kid=path pairs separated by commas
+	for _, entry := range strings.Split(viper.GetString("JWT_PUBLIC_KEY_FILES"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path, ok := strings.Cut(entry, "=")
+		if !ok {
+			log.Fatalf("Invalid JWT public key entry %q, expected kid=path", entry)
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read JWT public key %s: %v", kid, err)
+			return nil
+		}
+
+		config.PublicKeys[kid], err = jwt.ParsePublicKeyPEM(data)
+		if err != nil {
+			log.Fatalf("Failed to parse JWT public key %s: %v", kid, err)
+			return nil
+		}
 	}
+
+	return config
 }
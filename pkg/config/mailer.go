@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/savioruz/bake/pkg/mailer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewMailer(viper *viper.Viper, log *logrus.Logger) mailer.Mailer {
+	config := &mailer.Config{
+		Driver:   viper.GetString("MAILER_DRIVER"),
+		From:     viper.GetString("MAIL_FROM"),
+		Host:     viper.GetString("SMTP_HOST"),
+		Port:     viper.GetInt("SMTP_PORT"),
+		Username: viper.GetString("SMTP_USERNAME"),
+		Password: viper.GetString("SMTP_PASSWORD"),
+		Dir:      viper.GetString("MAILER_DIR"),
+	}
+
+	switch config.Driver {
+	case "", "log":
+		return mailer.NewLogMailer(log)
+	case "smtp":
+		if config.Host == "" || config.From == "" {
+			log.Fatalf("SMTP_HOST and MAIL_FROM are required for the smtp mailer")
+			return nil
+		}
+		if config.Port == 0 {
+			config.Port = 587
+		}
+		return mailer.NewSMTPMailer(config)
+	case "file":
+		if config.Dir == "" {
+			config.Dir = "mail"
+		}
+		return mailer.NewFileMailer(config)
+	default:
+		log.Fatalf("Unknown mailer driver: %s", config.Driver)
+		return nil
+	}
+}
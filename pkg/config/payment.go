@@ -0,0 +1,50 @@
+package config
+
+import (
+	"github.com/savioruz/bake/pkg/payment"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewPayment(viper *viper.Viper, log *logrus.Logger) payment.PaymentGateway {
+	config := &payment.Config{
+		Gateway:       viper.GetString("PAYMENT_GATEWAY"),
+		WebhookSecret: viper.GetString("PAYMENT_WEBHOOK_SECRET"),
+		Currency:      viper.GetString("PAYMENT_CURRENCY"),
+	}
+
+	if config.WebhookSecret == "" {
+		log.Fatalf("PAYMENT_WEBHOOK_SECRET is required")
+		return nil
+	}
+	if config.Currency == "" {
+		config.Currency = "IDR"
+	}
+
+	// The sandbox settles payments without money changing hands, so it has to be chosen explicitly
+	switch config.Gateway {
+	case "":
+		log.Fatalf("PAYMENT_GATEWAY is required, set it to sandbox for development")
+		return nil
+	case "sandbox":
+		return payment.NewSandboxGateway(config)
+	default:
+		log.Fatalf("Unknown payment gateway: %s", config.Gateway)
+		return nil
+	}
+}
+
+// paymentSimulation reports whether PAYMENT_SIMULATION is enabled, which exposes an endpoint that
+// marks sandbox payments as paid. It is meant for development and only works with the sandbox gateway.
+func paymentSimulation(viper *viper.Viper, log *logrus.Logger) bool {
+	if !viper.GetBool("PAYMENT_SIMULATION") {
+		return false
+	}
+	if viper.GetString("PAYMENT_GATEWAY") != "sandbox" {
+		log.Fatalf("PAYMENT_SIMULATION requires PAYMENT_GATEWAY=sandbox")
+		return false
+	}
+
+	log.Warn("Payment simulation is enabled, order owners can mark their own payments as paid")
+	return true
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const defaultRoleCacheTTL = time.Minute
+
+func roleCacheTTL(viper *viper.Viper) time.Duration {
+	if ttl := viper.GetDuration("ROLE_CACHE_TTL"); ttl > 0 {
+		return ttl
+	}
+	return defaultRoleCacheTTL
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"github.com/rs/cors"
 	"github.com/savioruz/bake/internal/builder"
 	e "github.com/savioruz/bake/pkg/error"
+	"github.com/savioruz/bake/pkg/helper"
+	"github.com/savioruz/bake/pkg/middleware"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -27,6 +30,17 @@ func NewServer(viper *viper.Viper, log *logrus.Logger) *Server {
 	}
 }
 
+// trustedProxies reads TRUSTED_PROXIES, a comma separated list of IPs or CIDRs whose forwarding
+// headers are believed. Leave it empty when the app is not behind a proxy.
+func trustedProxies(viper *viper.Viper, log *logrus.Logger) []*net.IPNet {
+	proxies, err := helper.ParseTrustedProxies(strings.Split(viper.GetString("TRUSTED_PROXIES"), ","))
+	if err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+		return nil
+	}
+	return proxies
+}
+
 // LoggingMiddleware wraps an http.HandlerFunc and logs request details
 func (s *Server) LoggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -69,103 +83,60 @@ func (s *Server) chainMiddleware(handler http.HandlerFunc, middlewares ...func(h
 	return handler
 }
 
+// RegisterRoutes registers every route as a Go 1.22 ServeMux pattern, e.g. "GET /api/v1/products/{id}",
+// so handlers read path parameters with r.PathValue
 func (s *Server) RegisterRoutes(routes []builder.Routes) {
-	// Create API router for /api/v1 group
-	apiRouter := &Router{
-		prefix: "/api/v1",
-		routes: make(map[string]http.HandlerFunc),
-		log:    s.log,
-	}
-
-	// Register API routes
 	for _, route := range routes {
-		// Skip Swagger routes
+		pattern := route.Method + " " + route.Path
+
+		// Swagger routes are registered without logging
 		if strings.Contains(route.Path, "/docs") {
+			s.mux.HandleFunc(pattern, route.Handler)
 			continue
 		}
 
-		path := strings.TrimPrefix(route.Path, "/api/v1")
-
-		handler := s.chainMiddleware(
-			route.Handler,
-			s.LoggingMiddleware,
-		)
-
-		apiRouter.Handle(route.Method, path, handler)
+		s.mux.HandleFunc(pattern, s.chainMiddleware(route.Handler, s.LoggingMiddleware))
 	}
-
-	// Register Swagger routes directly
-	for _, route := range routes {
-		if strings.Contains(route.Path, "/docs") {
-			s.mux.HandleFunc(route.Path, route.Handler)
-		}
-	}
-
-	// Register API handler
-	s.mux.HandleFunc("/api/v1/", apiRouter.ServeHTTP)
-}
-
-// Router handles /api/v1 routes
-type Router struct {
-	prefix string
-	routes map[string]http.HandlerFunc
-	log    *logrus.Logger
-}
-
-func (r *Router) Handle(method, path string, handler http.HandlerFunc) {
-	key := method + ":" + path
-	r.routes[key] = handler
 }
 
-func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	// Remove prefix from path
-	path := strings.TrimPrefix(req.URL.Path, r.prefix)
-
-	// Try exact match first
-	key := req.Method + ":" + path
-	if handler, ok := r.routes[key]; ok {
-		handler(w, req)
+// ServeHTTP dispatches to the matching route and answers unmatched requests with JSON errors,
+// a path that exists for other methods gets 405 with an Allow header instead of 404
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := s.mux.Handler(r); pattern != "" {
+		s.mux.ServeHTTP(w, r)
 		return
 	}
 
-	// If no exact match, try matching patterns with parameters
-	for routeKey, handler := range r.routes {
-		routeMethod := strings.Split(routeKey, ":")[0]
-		routePath := strings.Split(routeKey, ":")[1]
-
-		// Skip if methods don't match
-		if routeMethod != req.Method {
-			continue
-		}
-
-		// Convert route pattern to regex
-		routeParts := strings.Split(routePath, "/")
-		pathParts := strings.Split(path, "/")
+	if allowed := s.allowedMethods(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		e.ErrorHandler(w, r, http.StatusMethodNotAllowed, e.ErrMethodNotAllowed)
+		return
+	}
 
-		if len(routeParts) != len(pathParts) {
-			continue
-		}
+	e.ErrorHandler(w, r, http.StatusNotFound, e.ErrRouteNotFound)
+}
 
-		matches := true
-		for i := 0; i < len(routeParts); i++ {
-			if strings.HasPrefix(routeParts[i], "{") && strings.HasSuffix(routeParts[i], "}") {
-				// This is a parameter, it matches anything
-				continue
-			}
-			if routeParts[i] != pathParts[i] {
-				matches = false
-				break
-			}
-		}
+// allowedMethods lists the methods that have a route for the path of the request
+func (s *Server) allowedMethods(r *http.Request) []string {
+	methods := []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
 
-		if matches {
-			handler(w, req)
-			return
+	var allowed []string
+	for _, method := range methods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := s.mux.Handler(probe); pattern != "" {
+			allowed = append(allowed, method)
 		}
 	}
 
-	// No route found
-	e.ErrorHandler(w, req, http.StatusNotFound, e.ErrRouteNotFound)
+	return allowed
 }
 
 func (s *Server) Start() error {
@@ -174,13 +145,13 @@ func (s *Server) Start() error {
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", middleware.IdempotencyKeyHeader},
 		AllowCredentials: true,
 		Debug:            false,
 	})
 
-	handler := corsHandler.Handler(s.mux)
+	handler := corsHandler.Handler(s)
 
 	return http.ListenAndServe(addr, handler)
 }
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"github.com/savioruz/bake/pkg/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewStorage(viper *viper.Viper, log *logrus.Logger) storage.BlobStore {
+	config := &storage.Config{
+		Driver:    viper.GetString("STORAGE_DRIVER"),
+		Dir:       viper.GetString("STORAGE_DIR"),
+		BaseURL:   viper.GetString("STORAGE_BASE_URL"),
+		Endpoint:  viper.GetString("S3_ENDPOINT"),
+		Region:    viper.GetString("S3_REGION"),
+		Bucket:    viper.GetString("S3_BUCKET"),
+		AccessKey: viper.GetString("S3_ACCESS_KEY"),
+		SecretKey: viper.GetString("S3_SECRET_KEY"),
+		PublicURL: viper.GetString("S3_PUBLIC_URL"),
+		PathStyle: viper.GetBool("S3_PATH_STYLE"),
+	}
+
+	switch config.Driver {
+	case "", "local":
+		if config.Dir == "" {
+			config.Dir = "uploads"
+		}
+		if config.BaseURL == "" {
+			config.BaseURL = "/uploads"
+		}
+		return storage.NewLocalStore(config)
+	case "s3":
+		if config.Bucket == "" || config.AccessKey == "" || config.SecretKey == "" {
+			log.Fatalf("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage")
+			return nil
+		}
+		if config.Region == "" {
+			config.Region = "us-east-1"
+		}
+		store, err := storage.NewS3Store(config)
+		if err != nil {
+			log.Fatalf("Failed to create s3 storage: %v", err)
+			return nil
+		}
+		return store
+	default:
+		log.Fatalf("Unknown storage driver: %s", config.Driver)
+		return nil
+	}
+}
+
+// uploadMaxSize is the largest image an admin may upload, e.g. "5MB"
+func uploadMaxSize(viper *viper.Viper) int64 {
+	if size := int64(viper.GetSizeInBytes("UPLOAD_MAX_SIZE")); size > 0 {
+		return size
+	}
+
+	return 5 << 20
+}
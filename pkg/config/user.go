@@ -0,0 +1,63 @@
+package config
+
+import (
+	"time"
+
+	"github.com/savioruz/bake/internal/service"
+	"github.com/savioruz/bake/pkg/jwt"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultPasswordResetTTL     = time.Hour
+	defaultEmailVerificationTTL = 48 * time.Hour
+
+	defaultLoginMaxAttempts      = 5
+	defaultLoginMaxAttemptsPerIP = 50
+	defaultLoginBackoffBase      = time.Second
+	defaultLoginLockout          = 15 * time.Minute
+)
+
+func NewUserConfig(viper *viper.Viper, jwtConfig *jwt.JWTConfig) *service.UserConfig {
+	config := &service.UserConfig{
+		AccessExpiry:         jwtConfig.AccessExpiry,
+		AppURL:               viper.GetString("APP_URL"),
+		PasswordResetURL:     viper.GetString("PASSWORD_RESET_URL"),
+		PasswordResetTTL:     viper.GetDuration("PASSWORD_RESET_TTL"),
+		EmailVerificationTTL: viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+
+		LoginMaxAttempts:      viper.GetInt("LOGIN_MAX_ATTEMPTS"),
+		LoginMaxAttemptsPerIP: viper.GetInt("LOGIN_MAX_ATTEMPTS_PER_IP"),
+		LoginBackoffBase:      viper.GetDuration("LOGIN_BACKOFF_BASE"),
+		LoginLockout:          viper.GetDuration("LOGIN_LOCKOUT"),
+
+		MFAIssuer: viper.GetString("MFA_ISSUER"),
+	}
+
+	if config.AppURL == "" {
+		config.AppURL = "http://localhost:" + viper.GetString("APP_PORT")
+	}
+	if config.PasswordResetTTL <= 0 {
+		config.PasswordResetTTL = defaultPasswordResetTTL
+	}
+	if config.EmailVerificationTTL <= 0 {
+		config.EmailVerificationTTL = defaultEmailVerificationTTL
+	}
+	if config.LoginMaxAttempts <= 0 {
+		config.LoginMaxAttempts = defaultLoginMaxAttempts
+	}
+	if config.LoginMaxAttemptsPerIP <= 0 {
+		config.LoginMaxAttemptsPerIP = defaultLoginMaxAttemptsPerIP
+	}
+	if config.LoginBackoffBase <= 0 {
+		config.LoginBackoffBase = defaultLoginBackoffBase
+	}
+	if config.LoginLockout <= 0 {
+		config.LoginLockout = defaultLoginLockout
+	}
+	if config.MFAIssuer == "" {
+		config.MFAIssuer = "Bake"
+	}
+
+	return config
+}
@@ -0,0 +1,36 @@
+package denylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps revoked tokens until they would have expired anyway
+type Store interface {
+	// Revoke denies a single token by its jti
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	// RevokeUser denies every token of a user issued before the given time, truncated to the second
+	// since that is all the precision iat carries
+	RevokeUser(ctx context.Context, userID string, before, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// RunGC deletes expired entries from the store on every tick until the context is done
+func RunGC(ctx context.Context, store Store, interval time.Duration, log *logrus.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := store.DeleteExpired(ctx, now); err != nil {
+				log.WithError(err).Error("Failed to delete expired denylist entries")
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type userRevocation struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+// MemoryStore keeps the denylist in process memory, it only suits a single instance deployment
+type MemoryStore struct {
+	mu     sync.RWMutex
+	leeway time.Duration
+	tokens map[string]time.Time
+	users  map[string]userRevocation
+}
+
+// NewMemoryStore keeps entries for leeway past their expiry, as long as the verifier still accepts the token
+func NewMemoryStore(leeway time.Duration) *MemoryStore {
+	return &MemoryStore{
+		leeway: leeway,
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]userRevocation),
+	}
+}
+
+func (s *MemoryStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt = expiresAt.Add(s.leeway)
+	if expiresAt.After(s.tokens[jti]) {
+		s.tokens[jti] = expiresAt
+	}
+	return nil
+}
+
+func (s *MemoryStore) RevokeUser(ctx context.Context, userID string, before, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before = before.Truncate(time.Second)
+	expiresAt = expiresAt.Add(s.leeway)
+	revocation := s.users[userID]
+	if before.After(revocation.before) {
+		revocation.before = before
+	}
+	if expiresAt.After(revocation.expiresAt) {
+		revocation.expiresAt = expiresAt
+	}
+	s.users[userID] = revocation
+	return nil
+}
+
+func (s *MemoryStore) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.tokens[jti]; ok {
+		return true, nil
+	}
+	if revocation, ok := s.users[userID]; ok && issuedAt.Before(revocation.before) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *MemoryStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jti, expiresAt := range s.tokens {
+		if expiresAt.Before(now) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, revocation := range s.users {
+		if revocation.expiresAt.Before(now) {
+			delete(s.users, userID)
+		}
+	}
+	return nil
+}
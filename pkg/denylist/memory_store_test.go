@@ -0,0 +1,89 @@
+package denylist
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreKeepsEntriesForLeeway(t *testing.T) {
+	const leeway = 30 * time.Second
+
+	ctx := context.Background()
+	store := NewMemoryStore(leeway)
+	now := time.Now()
+	expiresAt := now.Add(time.Minute)
+
+	if err := store.Revoke(ctx, "jti-1", expiresAt); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := store.RevokeUser(ctx, "user-1", now, expiresAt); err != nil {
+		t.Fatalf("RevokeUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		gcAt        time.Time
+		wantRevoked bool
+	}{
+		{name: "before expiry", gcAt: expiresAt.Add(-time.Second), wantRevoked: true},
+		{name: "expired but inside the leeway", gcAt: expiresAt.Add(leeway - time.Second), wantRevoked: true},
+		{name: "outside the leeway", gcAt: expiresAt.Add(leeway + time.Second), wantRevoked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.DeleteExpired(ctx, tt.gcAt); err != nil {
+				t.Fatalf("DeleteExpired() error = %v", err)
+			}
+
+			revoked, err := store.IsRevoked(ctx, "jti-1", "", now)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("token revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+
+			revoked, err = store.IsRevoked(ctx, "jti-2", "user-1", now.Add(-time.Second))
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("user revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRevokeUserComparesWholeSeconds(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(0)
+	now := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+
+	if err := store.RevokeUser(ctx, "user-1", now, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeUser() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		issuedAt    time.Time
+		wantRevoked bool
+	}{
+		{name: "issued the second before", issuedAt: now.Truncate(time.Second).Add(-time.Second), wantRevoked: true},
+		{name: "issued in the same second", issuedAt: now.Truncate(time.Second), wantRevoked: false},
+		{name: "issued the second after", issuedAt: now.Truncate(time.Second).Add(time.Second), wantRevoked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := store.IsRevoked(ctx, "jti-1", "user-1", tt.issuedAt)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if revoked != tt.wantRevoked {
+				t.Errorf("revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
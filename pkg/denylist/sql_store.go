@@ -0,0 +1,54 @@
+package denylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SQLStore keeps the denylist in the revoked_tokens and revoked_users tables so it is shared by every instance
+type SQLStore struct {
+	db     *sqlx.DB
+	leeway time.Duration
+}
+
+// NewSQLStore keeps entries for leeway past their expiry, as long as the verifier still accepts the token
+func NewSQLStore(db *sqlx.DB, leeway time.Duration) *SQLStore {
+	return &SQLStore{db: db, leeway: leeway}
+}
+
+func (s *SQLStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (jti, expires_at, created_at) VALUES (?, ?, ?)
+			  ON DUPLICATE KEY UPDATE expires_at = GREATEST(expires_at, VALUES(expires_at))`
+
+	_, err := s.db.ExecContext(ctx, query, jti, expiresAt.Add(s.leeway), time.Now())
+	return err
+}
+
+func (s *SQLStore) RevokeUser(ctx context.Context, userID string, before, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_users (user_id, revoked_before, expires_at) VALUES (?, ?, ?)
+			  ON DUPLICATE KEY UPDATE revoked_before = GREATEST(revoked_before, VALUES(revoked_before)),
+			  expires_at = GREATEST(expires_at, VALUES(expires_at))`
+
+	_, err := s.db.ExecContext(ctx, query, userID, before.Truncate(time.Second), expiresAt.Add(s.leeway))
+	return err
+}
+
+func (s *SQLStore) IsRevoked(ctx context.Context, jti, userID string, issuedAt time.Time) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?)
+			  OR EXISTS(SELECT 1 FROM revoked_users WHERE user_id = ? AND revoked_before > ?)`
+
+	var revoked bool
+	err := s.db.GetContext(ctx, &revoked, query, jti, userID, issuedAt)
+	return revoked, err
+}
+
+func (s *SQLStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < ?`, now); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM revoked_users WHERE expires_at < ?`, now)
+	return err
+}
@@ -1,6 +1,9 @@
 package error
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound      = errors.New("user not found")
@@ -9,8 +12,63 @@ var (
 	ErrCredential        = errors.New("invalid credential")
 	ErrMethodNotAllowed  = errors.New("method not allowed")
 	ErrUnauthorized      = errors.New("unauthorized")
+	ErrForbidden         = errors.New("forbidden")
 	ErrInternalServer    = errors.New("internal server error")
 	ErrNotFound          = errors.New("not found")
 	ErrRouteNotFound     = errors.New("route not found")
 	ErrInsufficientStock = errors.New("insufficient stock")
+
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrCartEmpty               = errors.New("cart is empty")
+	ErrAddressNotFound         = errors.New("address not found")
+	ErrAddressInUse            = errors.New("address is used by an order")
+	ErrOrderNotPayable         = errors.New("order cannot be paid in its current status")
+	ErrInvalidSignature        = errors.New("invalid webhook signature")
+	ErrSimulationUnavailable   = errors.New("payments can only be simulated with the sandbox gateway")
+	ErrIdempotencyKeyMismatch  = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyInProgress   = errors.New("a request with this idempotency key is still in progress")
+	ErrRequestTooLarge         = errors.New("request body is too large")
+	ErrInvalidToken            = errors.New("invalid or expired token")
+	ErrEmailNotVerified        = errors.New("email address is not verified")
+	ErrAccountLocked           = errors.New("too many failed login attempts, try again later")
+	ErrMFAAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled           = errors.New("two-factor authentication is not enabled")
+	ErrInvalidMFACode          = errors.New("invalid two-factor code")
+	ErrRoleNotFound            = errors.New("role not found")
+	ErrRoleInUse               = errors.New("role is still assigned to users")
+	ErrRoleProtected           = errors.New("built-in role cannot be changed")
+	ErrOwnAccount              = errors.New("you cannot change the role or status of your own account")
+	ErrAccountDisabled         = errors.New("account is disabled")
+	ErrUserHasOrders           = errors.New("user has orders, disable the account instead")
+	ErrInvalidPassword         = errors.New("current password is incorrect")
+	ErrCategoryNotFound        = errors.New("category not found")
+	ErrCategoryInUse           = errors.New("category still has subcategories")
+	ErrInvalidCategoryParent   = errors.New("a category cannot be nested under itself or its subcategories")
+	ErrTagNotFound             = errors.New("tag not found")
+	ErrSlugExists              = errors.New("slug is already taken")
+	ErrVariantNotFound         = errors.New("variant not found")
+	ErrVariantRequired         = errors.New("a variant must be chosen for this product")
+	ErrOptionGroupNotFound     = errors.New("option group not found")
+	ErrInvalidOptions          = errors.New("invalid option selection")
+	ErrSKUExists               = errors.New("sku is already taken")
+	ErrImageNotFound           = errors.New("image not found")
+	ErrUnsupportedImage        = errors.New("unsupported image, upload a JPEG, PNG, GIF or WebP file")
+	ErrImageTooLarge           = errors.New("image is too large")
+	ErrTooManyImages           = errors.New("product has too many images")
+	ErrProductUnavailable      = errors.New("product is no longer available")
+	ErrProductNotDeleted       = errors.New("only deleted products can be purged")
+	ErrProductHasOrders        = errors.New("product has orders, keep it deleted instead")
 )
+
+// LockedError is an ErrAccountLocked that knows when the next attempt is allowed
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (err *LockedError) Error() string {
+	return ErrAccountLocked.Error()
+}
+
+func (err *LockedError) Unwrap() error {
+	return ErrAccountLocked
+}
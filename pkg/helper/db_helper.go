@@ -0,0 +1,16 @@
+package helper
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlRowIsReferenced is ER_ROW_IS_REFERENCED_2, a delete blocked by a foreign key
+const mysqlRowIsReferenced = 1451
+
+// IsForeignKeyViolation reports whether err is a delete or update refused because other rows still reference the row
+func IsForeignKeyViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlRowIsReferenced
+}
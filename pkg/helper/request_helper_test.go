@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("parsing trusted proxies: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedFor  string
+		realIP        string
+		noTrustedList bool
+		want          string
+	}{
+		{name: "direct client", remoteAddr: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "untrusted peer cannot forge forwarded for", remoteAddr: "203.0.113.7:5000", forwardedFor: "198.51.100.1", want: "203.0.113.7"},
+		{name: "untrusted peer cannot forge real ip", remoteAddr: "203.0.113.7:5000", realIP: "198.51.100.1", want: "203.0.113.7"},
+		{name: "headers ignored without trusted proxies", remoteAddr: "10.0.0.2:5000", forwardedFor: "198.51.100.1", noTrustedList: true, want: "10.0.0.2"},
+		{name: "trusted proxy forwards the client", remoteAddr: "10.0.0.2:5000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "spoofed hops left of the client are skipped", remoteAddr: "10.0.0.2:5000", forwardedFor: "1.1.1.1, 198.51.100.1, 10.0.0.3", want: "198.51.100.1"},
+		{name: "single trusted ip", remoteAddr: "192.168.1.1:5000", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "only trusted hops", remoteAddr: "10.0.0.2:5000", forwardedFor: "10.0.0.4, 10.0.0.3", want: "10.0.0.4"},
+		{name: "malformed hop stops the walk", remoteAddr: "10.0.0.2:5000", forwardedFor: "198.51.100.1, garbage", want: "10.0.0.2"},
+		{name: "trusted proxy sets real ip", remoteAddr: "10.0.0.2:5000", realIP: "198.51.100.1", want: "198.51.100.1"},
+		{name: "trusted proxy without headers", remoteAddr: "10.0.0.2:5000", want: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/users/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			proxies := trusted
+			if tt.noTrustedList {
+				proxies = nil
+			}
+			if got := ClientIP(r, proxies); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalid(t *testing.T) {
+	for _, proxy := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := ParseTrustedProxies([]string{proxy}); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want an error", proxy)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package helper
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
 
 func FormatTime(date time.Time) string {
 	return date.UTC().Add(time.Hour * 7).Format(time.RFC3339)
@@ -12,3 +18,25 @@ func StrToPtr(s string) *string {
 	}
 	return &s
 }
+
+// Slugify lowercases s, strips accents and joins its letters and digits with single dashes,
+// e.g. "Crème Brûlée" becomes "creme-brulee"
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -2,6 +2,8 @@ package jwt
 
 type JWTService interface {
 	GenerateAccessToken(userID, email, role string) (string, error)
-	GenerateRefreshToken(userID, email, role string) (string, error)
-	ValidateToken(tokenString string) (*JWTClaims, error)
+	GenerateRefreshToken(userID, email, role string) (string, *JWTClaims, error)
+	GenerateMFAToken(userID, email, role string) (string, *JWTClaims, error)
+	ValidateToken(tokenString, tokenType string) (*JWTClaims, error)
+	JWKS() *JWKSet
 }
@@ -1,74 +1,234 @@
 package jwt
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	// TokenTypeMFA is a short lived challenge that only proves the password of a two-factor login
+	TokenTypeMFA = "mfa"
+)
+
+var ErrInvalidTokenType = errors.New("invalid token type")
+
 type JWTConfig struct {
-	Secret        string
+	Secret     string
+	Algorithm  string
+	KeyID      string
+	PrivateKey crypto.PrivateKey
+	// PublicKeys holds retired keys by kid, tokens they signed stay valid while keys rotate
+	PublicKeys    map[string]crypto.PublicKey
 	AccessExpiry  time.Duration
 	RefreshExpiry time.Duration
+	MFAExpiry     time.Duration
+	Issuer        string
+	Audience      string
+	// Leeway tolerates clock skew between issuers and verifiers on exp, nbf and iat
+	Leeway time.Duration
 }
 
 type JWTClaims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	TokenType string `json:"token_type"`
 	jwt.RegisteredClaims
 }
 
 type JWTServiceImpl struct {
-	secretKey     []byte
+	method        jwt.SigningMethod
+	keyID         string
+	signingKey    interface{}
+	keys          map[string]verificationKey
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	mfaExpiry     time.Duration
+	issuer        string
+	audience      string
+	leeway        time.Duration
 }
 
-func NewJWTService(config *JWTConfig) *JWTServiceImpl {
-	return &JWTServiceImpl{
-		secretKey:     []byte(config.Secret),
+func NewJWTService(config *JWTConfig) (*JWTServiceImpl, error) {
+	s := &JWTServiceImpl{
+		keyID:         config.KeyID,
+		keys:          make(map[string]verificationKey),
 		accessExpiry:  config.AccessExpiry,
 		refreshExpiry: config.RefreshExpiry,
+		mfaExpiry:     config.MFAExpiry,
+		issuer:        config.Issuer,
+		audience:      config.Audience,
+		leeway:        config.Leeway,
+	}
+	if s.keyID == "" {
+		s.keyID = DefaultKeyID
+	}
+	// Every token names its issuer and audience so tokens of other services sharing a key are refused
+	if s.issuer == "" || s.audience == "" {
+		return nil, errors.New("an issuer and an audience are required")
+	}
+
+	switch config.Algorithm {
+	case "", AlgorithmHS256:
+		if config.Secret == "" {
+			return nil, errors.New("a secret is required for HS256")
+		}
+		s.method = jwt.SigningMethodHS256
+		s.signingKey = []byte(config.Secret)
+		s.keys[s.keyID] = verificationKey{method: s.method, key: s.signingKey}
+	case AlgorithmRS256, AlgorithmEdDSA:
+		signer, ok := config.PrivateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("a private key is required for %s", config.Algorithm)
+		}
+		current, err := newVerificationKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		if current.method.Alg() != config.Algorithm {
+			return nil, fmt.Errorf("private key does not match algorithm %s", config.Algorithm)
+		}
+		s.method = current.method
+		s.signingKey = signer
+		s.keys[s.keyID] = current
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", config.Algorithm)
+	}
+
+	for kid, publicKey := range config.PublicKeys {
+		if kid == s.keyID {
+			continue
+		}
+		key, err := newVerificationKey(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("public key %s: %w", kid, err)
+		}
+		s.keys[kid] = key
 	}
+
+	return s, nil
 }
 
 func (s *JWTServiceImpl) GenerateAccessToken(userID, email, role string) (string, error) {
-	return s.generateToken(userID, email, role, s.accessExpiry)
+	token, _, err := s.generateToken(userID, email, role, TokenTypeAccess, s.accessExpiry)
+	return token, err
+}
+
+// GenerateRefreshToken also returns the claims so the caller can store the token id and expiry
+func (s *JWTServiceImpl) GenerateRefreshToken(userID, email, role string) (string, *JWTClaims, error) {
+	return s.generateToken(userID, email, role, TokenTypeRefresh, s.refreshExpiry)
 }
 
-func (s *JWTServiceImpl) GenerateRefreshToken(userID, email, role string) (string, error) {
-	return s.generateToken(userID, email, role, s.refreshExpiry)
+// GenerateMFAToken also returns the claims so the caller can revoke the challenge once it is used
+func (s *JWTServiceImpl) GenerateMFAToken(userID, email, role string) (string, *JWTClaims, error) {
+	return s.generateToken(userID, email, role, TokenTypeMFA, s.mfaExpiry)
 }
 
-func (s *JWTServiceImpl) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.secretKey, nil
-	})
+// ValidateToken verifies a token and rejects it unless it is of the expected type
+func (s *JWTServiceImpl) ValidateToken(tokenString, tokenType string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, s.keyFunc, s.parserOptions()...)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if claims.TokenType != tokenType {
+			return nil, ErrInvalidTokenType
+		}
+		if claims.ID == "" || claims.IssuedAt == nil || claims.NotBefore == nil || claims.Subject != claims.UserID {
+			return nil, errors.New("invalid token")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
-func (s *JWTServiceImpl) generateToken(userID, email, role string, expiry time.Duration) (string, error) {
+func (s *JWTServiceImpl) generateToken(userID, email, role, tokenType string, expiry time.Duration) (string, *JWTClaims, error) {
+	now := time.Now()
 	claims := &JWTClaims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Issuer:    s.issuer,
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Audience:  jwt.ClaimStrings{s.audience},
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
+	token := jwt.NewWithClaims(s.method, claims)
+	token.Header["kid"] = s.keyID
+	signed, err := token.SignedString(s.signingKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, claims, nil
+}
+
+// JWKS publishes the public keys tokens can be verified with
+func (s *JWTServiceImpl) JWKS() *JWKSet {
+	set := &JWKSet{Keys: []JWK{}}
+	for _, kid := range sortedKeyIDs(s.keys) {
+		if jwk, ok := toJWK(kid, s.keys[kid]); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// keyFunc picks the key named by the kid header and refuses tokens signed with another method than the key's
+func (s *JWTServiceImpl) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = s.keyID
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.key, nil
+}
+
+// parserOptions requires exp and iat on every token and checks iss and aud
+func (s *JWTServiceImpl) parserOptions() []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithValidMethods(s.validMethods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+	}
+}
+
+func (s *JWTServiceImpl) validMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range s.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
 }
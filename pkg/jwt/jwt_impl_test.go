@@ -0,0 +1,284 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	testSecret   = "test-secret"
+	testIssuer   = "bake-api"
+	testAudience = "bake-web"
+	testLeeway   = 30 * time.Second
+)
+
+func newTestJWTService(t *testing.T) *JWTServiceImpl {
+	t.Helper()
+
+	service, err := NewJWTService(&JWTConfig{
+		Secret:        testSecret,
+		Algorithm:     AlgorithmHS256,
+		AccessExpiry:  time.Hour,
+		RefreshExpiry: 24 * time.Hour,
+		MFAExpiry:     5 * time.Minute,
+		Issuer:        testIssuer,
+		Audience:      testAudience,
+		Leeway:        testLeeway,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTService() error = %v", err)
+	}
+	return service
+}
+
+// validClaims are the claims of an access token the test service would issue right now
+func validClaims() *JWTClaims {
+	now := time.Now()
+	userID := uuid.NewString()
+	return &JWTClaims{
+		UserID:    userID,
+		Email:     "user@example.com",
+		Role:      "user",
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+}
+
+func signHS256(t *testing.T, claims *JWTClaims, secret string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = DefaultKeyID
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTServiceValidateToken(t *testing.T) {
+	service := newTestJWTService(t)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: func(t *testing.T) string { return signHS256(t, validClaims(), testSecret) },
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Issuer = "other-service"
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing issuer",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Issuer = ""
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"other-service"}
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing audience",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Audience = nil
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "alg none",
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims())
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatalf("signing token: %v", err)
+				}
+				return signed
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong secret",
+			token: func(t *testing.T) string {
+				return signHS256(t, validClaims(), "another-secret")
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired outside the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+				claims.NotBefore = claims.IssuedAt
+				claims.ExpiresAt = jwt.NewNumericDate(now.Add(-testLeeway - 10*time.Second))
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired inside the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.IssuedAt = jwt.NewNumericDate(now.Add(-time.Hour))
+				claims.NotBefore = claims.IssuedAt
+				claims.ExpiresAt = jwt.NewNumericDate(now.Add(-testLeeway + 10*time.Second))
+				return signHS256(t, claims, testSecret)
+			},
+		},
+		{
+			name: "missing expiry",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "not before in the future outside the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.NotBefore = jwt.NewNumericDate(now.Add(testLeeway + 10*time.Second))
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "not before in the future inside the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.NotBefore = jwt.NewNumericDate(now.Add(testLeeway - 10*time.Second))
+				return signHS256(t, claims, testSecret)
+			},
+		},
+		{
+			name: "issued in the future outside the leeway",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.IssuedAt = jwt.NewNumericDate(now.Add(testLeeway + 10*time.Second))
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong token type",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.TokenType = TokenTypeRefresh
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "subject does not match the user",
+			token: func(t *testing.T) string {
+				claims := validClaims()
+				claims.Subject = uuid.NewString()
+				return signHS256(t, claims, testSecret)
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown key id",
+			token: func(t *testing.T) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+				token.Header["kid"] = "retired"
+				signed, err := token.SignedString([]byte(testSecret))
+				if err != nil {
+					t.Fatalf("signing token: %v", err)
+				}
+				return signed
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ValidateToken(tt.token(t), TokenTypeAccess)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTServiceGeneratedTokens(t *testing.T) {
+	service := newTestJWTService(t)
+
+	token, err := service.GenerateAccessToken("user-1", "user@example.com", "user")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	claims, err := service.ValidateToken(token, TokenTypeAccess)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.Issuer != testIssuer {
+		t.Errorf("issuer = %q, want %q", claims.Issuer, testIssuer)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != testAudience {
+		t.Errorf("audience = %v, want [%s]", claims.Audience, testAudience)
+	}
+
+	if _, err := service.ValidateToken(token, TokenTypeRefresh); err == nil {
+		t.Error("ValidateToken() accepted an access token as a refresh token")
+	}
+}
+
+func TestNewJWTServiceRequiresIssuerAndAudience(t *testing.T) {
+	tests := []struct {
+		name     string
+		issuer   string
+		audience string
+	}{
+		{name: "missing issuer", audience: testAudience},
+		{name: "missing audience", issuer: testIssuer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWTService(&JWTConfig{
+				Secret:    testSecret,
+				Algorithm: AlgorithmHS256,
+				Issuer:    tt.issuer,
+				Audience:  tt.audience,
+			})
+			if err == nil {
+				t.Error("NewJWTService() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	DefaultKeyID = "default"
+)
+
+// JWK is a public key in the JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// verificationKey binds a key to the only method tokens signed with it may use
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// ParsePrivateKeyPEM parses the signing key for an asymmetric algorithm
+func ParsePrivateKeyPEM(algorithm string, data []byte) (crypto.PrivateKey, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.ParseRSAPrivateKeyFromPEM(data)
+	case AlgorithmEdDSA:
+		return jwt.ParseEdPrivateKeyFromPEM(data)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+// ParsePublicKeyPEM parses an RSA or Ed25519 public key
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported public key, expected RSA or Ed25519")
+}
+
+func newVerificationKey(key crypto.PublicKey) (verificationKey, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return verificationKey{method: jwt.SigningMethodRS256, key: key}, nil
+	case ed25519.PublicKey:
+		return verificationKey{method: jwt.SigningMethodEdDSA, key: key}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+func toJWK(kid string, key verificationKey) (JWK, bool) {
+	switch key := key.key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgorithmRS256,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgorithmEdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, true
+	default:
+		// Shared secrets are never published
+		return JWK{}, false
+	}
+}
+
+func sortedKeyIDs(keys map[string]verificationKey) []string {
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer drops every email as an .eml file into a directory, for tests and staging
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(config *Config) *FileMailer {
+	return &FileMailer{
+		dir:  config.Dir,
+		from: config.From,
+	}
+}
+
+func (m *FileMailer) Send(ctx context.Context, message *Message) error {
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, message), 0o640)
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSMTPMailerSendHonoursContext(t *testing.T) {
+	// The server accepts connections but never greets, so only ctx can end the send
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	m := NewSMTPMailer(&Config{From: "no-reply@bake.local", Host: host, Port: portNumber})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.Send(ctx, &Message{To: "user@example.com", Subject: "Hello", Body: "Hi"})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Send() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send() did not return after the context expired")
+	}
+}
+
+func TestSMTPMailerSendRejectsHeaderInjection(t *testing.T) {
+	m := NewSMTPMailer(&Config{From: "no-reply@bake.local", Host: "127.0.0.1", Port: 1})
+	err := m.Send(context.Background(), &Message{To: "user@example.com\r\nBcc: other@example.com", Subject: "Hello"})
+	if err == nil {
+		t.Fatal("Send() accepted a recipient with a line break")
+	}
+}
@@ -4,21 +4,27 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/savioruz/bake/pkg/denylist"
 	e "github.com/savioruz/bake/pkg/error"
 	"github.com/savioruz/bake/pkg/jwt"
 	"github.com/sirupsen/logrus"
 )
 
 type AuthMiddleware struct {
-	jwtService jwt.JWTService
-	log        *logrus.Logger
+	jwtService  jwt.JWTService
+	denylist    denylist.Store
+	permissions PermissionProvider
+	log         *logrus.Logger
 }
 
-func NewAuthMiddleware(jwtService jwt.JWTService, log *logrus.Logger) *AuthMiddleware {
+func NewAuthMiddleware(jwtService jwt.JWTService, denylist denylist.Store, permissions PermissionProvider, log *logrus.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
-		log:        log,
+		jwtService:  jwtService,
+		denylist:    denylist,
+		permissions: permissions,
+		log:         log,
 	}
 }
 
@@ -26,9 +32,12 @@ func NewAuthMiddleware(jwtService jwt.JWTService, log *logrus.Logger) *AuthMiddl
 type contextKey string
 
 const (
-	UserIDKey contextKey = "user_id"
-	EmailKey  contextKey = "email"
-	RoleKey   contextKey = "role"
+	UserIDKey         contextKey = "user_id"
+	EmailKey          contextKey = "email"
+	RoleKey           contextKey = "role"
+	TokenIDKey        contextKey = "token_id"
+	TokenExpiresAtKey contextKey = "token_expires_at"
+	PermissionsKey    contextKey = "permissions"
 )
 
 func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
@@ -51,17 +60,40 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 
 		// Validate token
 		token := parts[1]
-		claims, err := m.jwtService.ValidateToken(token)
+		claims, err := m.jwtService.ValidateToken(token, jwt.TokenTypeAccess)
 		if err != nil {
 			m.log.WithError(err).Warn("Invalid token")
 			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
 			return
 		}
 
+		// Reject tokens revoked by a logout
+		revoked, err := m.denylist.IsRevoked(r.Context(), claims.ID, claims.UserID, claims.IssuedAt.Time)
+		if err != nil {
+			m.log.WithError(err).Error("Failed to check token denylist")
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+			return
+		}
+		if revoked {
+			m.log.Warn("Revoked token")
+			e.ErrorHandler(w, r, http.StatusUnauthorized, e.ErrUnauthorized)
+			return
+		}
+
+		permissions, err := m.permissions.Permissions(r.Context(), claims.Role)
+		if err != nil {
+			m.log.WithError(err).Error("Failed to load role permissions")
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+			return
+		}
+
 		// Add claims to context
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 		ctx = context.WithValue(ctx, EmailKey, claims.Email)
 		ctx = context.WithValue(ctx, RoleKey, claims.Role)
+		ctx = context.WithValue(ctx, TokenIDKey, claims.ID)
+		ctx = context.WithValue(ctx, TokenExpiresAtKey, claims.ExpiresAt.Time)
+		ctx = context.WithValue(ctx, PermissionsKey, permissions)
 
 		// Call next handler with updated context
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -69,12 +101,20 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 }
 
 func (m *AuthMiddleware) RequireRole(roles []string, next http.HandlerFunc) http.HandlerFunc {
+	return m.Authorize(roles, nil, next)
+}
+
+func (m *AuthMiddleware) RequirePermission(permissions []string, next http.HandlerFunc) http.HandlerFunc {
+	return m.Authorize(nil, permissions, next)
+}
+
+// Authorize requires an authenticated caller with one of the roles, when any are given,
+// and every one of the permissions
+func (m *AuthMiddleware) Authorize(roles, permissions []string, next http.HandlerFunc) http.HandlerFunc {
 	return m.RequireAuth(func(w http.ResponseWriter, r *http.Request) {
-		// Get role from context
-		role := r.Context().Value(RoleKey).(string)
+		role := GetRoleFromContext(r.Context())
 
-		// Check if user's role is allowed
-		allowed := false
+		allowed := len(roles) == 0
 		for _, allowedRole := range roles {
 			if role == allowedRole {
 				allowed = true
@@ -82,12 +122,20 @@ func (m *AuthMiddleware) RequireRole(roles []string, next http.HandlerFunc) http
 			}
 		}
 
+		for _, permission := range permissions {
+			if !HasPermission(r.Context(), permission) {
+				allowed = false
+				break
+			}
+		}
+
 		if !allowed {
 			m.log.WithFields(logrus.Fields{
-				"required_roles": roles,
-				"user_role":      role,
+				"required_roles":       roles,
+				"required_permissions": permissions,
+				"user_role":            role,
 			}).Warn("Insufficient permissions")
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			e.ErrorHandler(w, r, http.StatusForbidden, e.ErrForbidden)
 			return
 		}
 
@@ -116,3 +164,17 @@ func GetRoleFromContext(ctx context.Context) string {
 	}
 	return ""
 }
+
+func GetTokenIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(TokenIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+func GetTokenExpiresAtFromContext(ctx context.Context) time.Time {
+	if expiresAt, ok := ctx.Value(TokenExpiresAtKey).(time.Time); ok {
+		return expiresAt
+	}
+	return time.Time{}
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/savioruz/bake/internal/domain/entity"
+	e "github.com/savioruz/bake/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize bounds the body that is buffered to fingerprint a request
+	maxIdempotentBodySize = 1 << 20
+)
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, key *entity.IdempotencyKey) error
+	Release(ctx context.Context, keyHash string) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type IdempotencyMiddleware struct {
+	store IdempotencyStore
+	ttl   time.Duration
+	log   *logrus.Logger
+}
+
+func NewIdempotencyMiddleware(store IdempotencyStore, ttl time.Duration, log *logrus.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store: store,
+		ttl:   ttl,
+		log:   log,
+	}
+}
+
+// Handle replays the stored response when a request is retried with the same Idempotency-Key,
+// requests without the header are passed through untouched. On private routes it has to run
+// inside RequireAuth, keys are scoped to the authenticated user
+func (m *IdempotencyMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			e.ErrorHandler(w, r, http.StatusBadRequest, e.ErrValidation)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				e.ErrorHandler(w, r, http.StatusRequestEntityTooLarge, e.ErrRequestTooLarge)
+				return
+			}
+			e.ErrorHandler(w, r, http.StatusBadRequest, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the user and the route so clients can never replay each other's responses,
+		// and a retry with a refreshed access token still finds the first attempt
+		record := &entity.IdempotencyKey{
+			KeyHash:     hash(r.Method, r.URL.Path, GetUserIDFromContext(r.Context()), key),
+			Fingerprint: hash(string(body)),
+			ExpiresAt:   time.Now().Add(m.ttl),
+		}
+
+		existing, reserved, err := m.store.Reserve(r.Context(), record)
+		if err != nil {
+			m.log.WithError(err).Error("Failed to reserve idempotency key")
+			e.ErrorHandler(w, r, http.StatusInternalServerError, e.ErrInternalServer)
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				e.ErrorHandler(w, r, http.StatusUnprocessableEntity, e.ErrIdempotencyKeyMismatch)
+			case !existing.StatusCode.Valid:
+				e.ErrorHandler(w, r, http.StatusConflict, e.ErrIdempotencyInProgress)
+			default:
+				if existing.ContentType.Valid {
+					w.Header().Set("Content-Type", existing.ContentType.String)
+				}
+				w.Header().Set(IdempotencyReplayedHeader, "true")
+				w.WriteHeader(int(existing.StatusCode.Int64))
+				w.Write(existing.ResponseBody)
+			}
+			return
+		}
+
+		recorder := &recordingResponseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		// A panicking handler must not leave the key reserved until it expires
+		ctx := context.WithoutCancel(r.Context())
+		completed := false
+		defer func() {
+			if !completed {
+				m.release(ctx, record.KeyHash)
+			}
+		}()
+
+		next(recorder, r)
+
+		if !storable(recorder.statusCode) {
+			return
+		}
+
+		record.StatusCode = sql.NullInt64{Int64: int64(recorder.statusCode), Valid: true}
+		record.ContentType = sql.NullString{String: w.Header().Get("Content-Type"), Valid: true}
+		record.ResponseBody = recorder.body.Bytes()
+		if err := m.store.Complete(ctx, record); err != nil {
+			m.log.WithError(err).Error("Failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+// RunGC deletes expired keys on every tick until the context is done
+func (m *IdempotencyMiddleware) RunGC(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := m.store.DeleteExpired(ctx, now); err != nil {
+				m.log.WithError(err).Error("Failed to delete expired idempotency keys")
+			}
+		}
+	}
+}
+
+// release frees a key so the request can be retried
+func (m *IdempotencyMiddleware) release(ctx context.Context, keyHash string) {
+	if err := m.store.Release(ctx, keyHash); err != nil {
+		m.log.WithError(err).Error("Failed to release idempotency key")
+	}
+}
+
+// storable reports whether a response is replayed on retries. Authentication failures and
+// server errors are not, the client can fix its token or retry once the server recovers
+func storable(statusCode int) bool {
+	switch {
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return false
+	case statusCode >= http.StatusInternalServerError:
+		return false
+	default:
+		return true
+	}
+}
+
+// recordingResponseWriter keeps a copy of the response while writing it to the client
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+func hash(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/savioruz/bake/internal/domain/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// memoryIdempotencyStore is an IdempotencyStore backed by a map
+type memoryIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]entity.IdempotencyKey
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: make(map[string]entity.IdempotencyKey)}
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.keys[key.KeyHash]; ok {
+		return &existing, false, nil
+	}
+	s.keys[key.KeyHash] = *key
+	return key, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.KeyHash] = *key
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, keyHash)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, key := range s.keys {
+		if key.ExpiresAt.Before(now) {
+			delete(s.keys, hash)
+		}
+	}
+	return nil
+}
+
+func newTestIdempotencyMiddleware(store IdempotencyStore) *IdempotencyMiddleware {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return NewIdempotencyMiddleware(store, time.Hour, log)
+}
+
+// idempotentRequest sends a POST with the given key as the given user and returns the response
+func idempotentRequest(handler http.HandlerFunc, userID, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"items":[]}`))
+	r.Header.Set(IdempotencyKeyHeader, key)
+	if userID != "" {
+		r = r.WithContext(context.WithValue(r.Context(), UserIDKey, userID))
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestIdempotencyMiddlewareReplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		firstUser  string
+		secondUser string
+		wantCalls  int
+	}{
+		{name: "created is replayed for the same user", status: http.StatusCreated, firstUser: "user-1", secondUser: "user-1", wantCalls: 1},
+		{name: "other users do not share keys", status: http.StatusCreated, firstUser: "user-1", secondUser: "user-2", wantCalls: 2},
+		{name: "client errors are replayed", status: http.StatusBadRequest, firstUser: "user-1", secondUser: "user-1", wantCalls: 1},
+		{name: "unauthorized is not stored", status: http.StatusUnauthorized, firstUser: "user-1", secondUser: "user-1", wantCalls: 2},
+		{name: "forbidden is not stored", status: http.StatusForbidden, firstUser: "user-1", secondUser: "user-1", wantCalls: 2},
+		{name: "server errors are not stored", status: http.StatusInternalServerError, firstUser: "user-1", secondUser: "user-1", wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := newTestIdempotencyMiddleware(newMemoryIdempotencyStore()).Handle(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+			})
+
+			idempotentRequest(handler, tt.firstUser, "key-1")
+			second := idempotentRequest(handler, tt.secondUser, "key-1")
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if second.Code != tt.status {
+				t.Errorf("second status = %d, want %d", second.Code, tt.status)
+			}
+			if replayed := second.Header().Get(IdempotencyReplayedHeader) == "true"; replayed != (tt.wantCalls == 1) {
+				t.Errorf("second replayed = %v, want %v", replayed, tt.wantCalls == 1)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	middleware := newTestIdempotencyMiddleware(store)
+
+	panicking := middleware.Handle(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler failed")
+	})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the panic to propagate")
+			}
+		}()
+		idempotentRequest(panicking, "user-1", "key-1")
+	}()
+
+	if len(store.keys) != 0 {
+		t.Fatalf("reserved keys after panic = %d, want 0", len(store.keys))
+	}
+
+	retried := middleware.Handle(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	if w := idempotentRequest(retried, "user-1", "key-1"); w.Code != http.StatusCreated {
+		t.Errorf("retry status = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsLargeBodies(t *testing.T) {
+	calls := 0
+	handler := newTestIdempotencyMiddleware(newMemoryIdempotencyStore()).Handle(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(strings.Repeat("a", maxIdempotentBodySize+1)))
+	r.Header.Set(IdempotencyKeyHeader, "key-1")
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+	if calls != 0 {
+		t.Errorf("handler calls = %d, want 0", calls)
+	}
+}
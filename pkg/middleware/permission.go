@@ -0,0 +1,35 @@
+package middleware
+
+import "context"
+
+// Permissions granted to roles through the role_permissions table
+const (
+	PermissionProductsWrite      = "products:write"
+	PermissionOrdersReadAll      = "orders:read_all"
+	PermissionOrdersUpdateStatus = "orders:update_status"
+	PermissionUsersRead          = "users:read"
+	PermissionUsersWrite         = "users:write"
+	PermissionRolesManage        = "roles:manage"
+)
+
+// PermissionProvider resolves the permissions granted to a role
+type PermissionProvider interface {
+	Permissions(ctx context.Context, role string) ([]string, error)
+}
+
+// HasPermission reports whether the authenticated caller was granted a permission
+func HasPermission(ctx context.Context, permission string) bool {
+	for _, granted := range GetPermissionsFromContext(ctx) {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func GetPermissionsFromContext(ctx context.Context) []string {
+	if permissions, ok := ctx.Value(PermissionsKey).([]string); ok {
+		return permissions
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	IntentStatusPending   = "pending"
+	IntentStatusSucceeded = "succeeded"
+	IntentStatusFailed    = "failed"
+	IntentStatusRefunded  = "refunded"
+
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventPaymentRefunded  = "payment.refunded"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrIntentNotFound   = errors.New("payment intent not found")
+	ErrUnsupportedEvent = errors.New("event type cannot be simulated")
+)
+
+// PaymentGateway is implemented by every payment provider the shop can charge through
+type PaymentGateway interface {
+	Name() string
+	CreateIntent(ctx context.Context, reference string, amount float64) (*Intent, error)
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	Refund(ctx context.Context, intentID string, amount float64) (*Intent, error)
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// Simulator is implemented by offline gateways that can play the customer's side of a payment,
+// answering with the signed webhook the real gateway would have sent
+type Simulator interface {
+	Simulate(ctx context.Context, intentID, eventType string) (payload []byte, signature string, err error)
+}
+
+type Intent struct {
+	ID           string  `json:"id"`
+	Reference    string  `json:"reference"`
+	Amount       float64 `json:"amount"`
+	Currency     string  `json:"currency"`
+	Status       string  `json:"status"`
+	ClientSecret string  `json:"client_secret,omitempty"`
+}
+
+// Event is a webhook notification sent by the gateway about an intent
+type Event struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	IntentID  string  `json:"intent_id"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+}
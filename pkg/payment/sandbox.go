@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	signaturePrefix = "sha256="
+	intentPrefix    = "pi_sandbox_"
+)
+
+type Config struct {
+	Gateway       string
+	WebhookSecret string
+	Currency      string
+}
+
+// SandboxGateway is an offline gateway that keeps intents in memory and signs webhooks with a shared secret
+type SandboxGateway struct {
+	secret   []byte
+	currency string
+	mu       sync.Mutex
+	intents  map[string]*Intent
+}
+
+func NewSandboxGateway(config *Config) *SandboxGateway {
+	return &SandboxGateway{
+		secret:   []byte(config.WebhookSecret),
+		currency: config.Currency,
+		intents:  make(map[string]*Intent),
+	}
+}
+
+func (g *SandboxGateway) Name() string {
+	return "sandbox"
+}
+
+func (g *SandboxGateway) CreateIntent(ctx context.Context, reference string, amount float64) (*Intent, error) {
+	id := intentPrefix + strings.ReplaceAll(uuid.NewString(), "-", "")
+	intent := &Intent{
+		ID:           id,
+		Reference:    reference,
+		Amount:       amount,
+		Currency:     g.currency,
+		Status:       IntentStatusPending,
+		ClientSecret: id + "_secret_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.intents[id] = intent
+
+	copied := *intent
+	return &copied, nil
+}
+
+func (g *SandboxGateway) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	return g.setStatus(intentID, IntentStatusSucceeded)
+}
+
+func (g *SandboxGateway) Refund(ctx context.Context, intentID string, amount float64) (*Intent, error) {
+	return g.setStatus(intentID, IntentStatusRefunded)
+}
+
+// Simulate completes or fails an intent as if the customer paid, returning the signed webhook for it
+func (g *SandboxGateway) Simulate(ctx context.Context, intentID, eventType string) ([]byte, string, error) {
+	var intent *Intent
+	var err error
+	switch eventType {
+	case EventPaymentSucceeded:
+		intent, err = g.Capture(ctx, intentID)
+	case EventPaymentFailed:
+		intent, err = g.setStatus(intentID, IntentStatusFailed)
+	default:
+		return nil, "", ErrUnsupportedEvent
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload, err := json.Marshal(&Event{
+		ID:        "evt_sandbox_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Type:      eventType,
+		IntentID:  intent.ID,
+		Reference: intent.Reference,
+		Amount:    intent.Amount,
+		Currency:  intent.Currency,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, Sign(g.secret, payload), nil
+}
+
+func (g *SandboxGateway) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	expected := Sign(g.secret, payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	return &event, nil
+}
+
+func (g *SandboxGateway) setStatus(intentID, status string) (*Intent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Intents only live in memory, so one created before a restart is picked up again
+	// instead of failing every later capture or refund
+	intent, ok := g.intents[intentID]
+	if !ok {
+		if !strings.HasPrefix(intentID, intentPrefix) {
+			return nil, ErrIntentNotFound
+		}
+		intent = &Intent{ID: intentID, Currency: g.currency}
+		g.intents[intentID] = intent
+	}
+	intent.Status = status
+
+	copied := *intent
+	return &copied, nil
+}
+
+// Sign computes the signature header value for a webhook payload
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
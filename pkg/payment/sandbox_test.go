@@ -0,0 +1,105 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSandboxGatewaySimulate(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventType  string
+		wantStatus string
+		wantErr    error
+	}{
+		{name: "succeeded", eventType: EventPaymentSucceeded, wantStatus: IntentStatusSucceeded},
+		{name: "failed", eventType: EventPaymentFailed, wantStatus: IntentStatusFailed},
+		{name: "refunded is not simulated", eventType: EventPaymentRefunded, wantErr: ErrUnsupportedEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gateway := NewSandboxGateway(&Config{WebhookSecret: "secret", Currency: "IDR"})
+
+			intent, err := gateway.CreateIntent(ctx, "order-1", 25000)
+			if err != nil {
+				t.Fatalf("CreateIntent() error = %v", err)
+			}
+
+			payload, signature, err := gateway.Simulate(ctx, intent.ID, tt.eventType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Simulate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			event, err := gateway.VerifyWebhook(payload, signature)
+			if err != nil {
+				t.Fatalf("VerifyWebhook() error = %v", err)
+			}
+			if event.Type != tt.eventType || event.IntentID != intent.ID || event.Reference != "order-1" || event.Amount != 25000 {
+				t.Errorf("VerifyWebhook() event = %+v", event)
+			}
+			if got := gateway.intents[intent.ID].Status; got != tt.wantStatus {
+				t.Errorf("intent status = %q, want %q", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSandboxGatewayVerifyWebhookRejectsTampering(t *testing.T) {
+	ctx := context.Background()
+	gateway := NewSandboxGateway(&Config{WebhookSecret: "secret", Currency: "IDR"})
+
+	intent, _ := gateway.CreateIntent(ctx, "order-1", 25000)
+	payload, signature, err := gateway.Simulate(ctx, intent.ID, EventPaymentSucceeded)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+
+	var event Event
+	json.Unmarshal(payload, &event)
+	event.Amount = 1
+	tampered, _ := json.Marshal(&event)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+	}{
+		{name: "tampered payload", payload: tampered, signature: signature},
+		{name: "missing signature", payload: payload, signature: ""},
+		{name: "other secret", payload: payload, signature: Sign([]byte("other"), payload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gateway.VerifyWebhook(tt.payload, tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifyWebhook() error = %v, want %v", err, ErrInvalidSignature)
+			}
+		})
+	}
+}
+
+func TestSandboxGatewayRefundAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	intent, _ := NewSandboxGateway(&Config{WebhookSecret: "secret"}).CreateIntent(ctx, "order-1", 25000)
+
+	// A fresh gateway has no intents in memory, as after a restart
+	gateway := NewSandboxGateway(&Config{WebhookSecret: "secret"})
+	refunded, err := gateway.Refund(ctx, intent.ID, 25000)
+	if err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+	if refunded.Status != IntentStatusRefunded {
+		t.Errorf("Refund() status = %q, want %q", refunded.Status, IntentStatusRefunded)
+	}
+
+	if _, err := gateway.Refund(ctx, "pi_other_123", 25000); !errors.Is(err, ErrIntentNotFound) {
+		t.Errorf("Refund() of a foreign intent error = %v, want %v", err, ErrIntentNotFound)
+	}
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"strings"
+)
+
+type Config struct {
+	Driver string
+	// Dir and BaseURL configure the local store, BaseURL is where Dir is served from
+	Dir     string
+	BaseURL string
+	// Endpoint, Region, Bucket and the keys configure the s3 store, PublicURL overrides
+	// the endpoint for links when the bucket sits behind a CDN
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+	PathStyle bool
+}
+
+// BlobStore keeps uploaded files under slash separated keys, e.g. "products/<id>/<file>.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// joinURL joins a base URL and a key without doubling slashes
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(key, "/")
+}